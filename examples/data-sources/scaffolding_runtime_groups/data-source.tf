@@ -0,0 +1,15 @@
+data "scaffolding_runtime_groups" "all" {}
+
+# Generates an import block for every existing runtime group, e.g. to onboard
+# them with `terraform plan -generate-config-out=generated.tf`. The runtime
+# groups are sorted by name, so the output is stable across runs.
+output "import_blocks" {
+  value = join("\n", [
+    for group in data.scaffolding_runtime_groups.all.runtime_groups : <<-EOT
+    import {
+      to = scaffolding_runtime_group.${replace(lower(group.name), "/[^a-z0-9_]/", "_")}
+      id = "${group.id}"
+    }
+    EOT
+  ])
+}
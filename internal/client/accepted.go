@@ -0,0 +1,223 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	// acceptedPollInterval is the delay between the polls of an accepted
+	// create, see CreateJob.Wait.
+	acceptedPollInterval = time.Second
+	// maxAcceptedPolls bounds the polls of an accepted create whose context
+	// has no deadline.
+	maxAcceptedPolls = 60
+)
+
+// The statuses of the job of an accepted create.
+const (
+	jobStatusPending   = "pending"
+	jobStatusRunning   = "running"
+	jobStatusSucceeded = "succeeded"
+	jobStatusFailed    = "failed"
+)
+
+// createJobStatus is the job document returned by a job location:
+// {"status": "pending", "runtime_group_id": "...", "error": "..."}.
+type createJobStatus struct {
+	// Status is the job status, a JSON string. It is absent, or not a string,
+	// when the location answers with the runtime group itself.
+	Status         json.RawMessage `json:"status"`
+	RuntimeGroupID string          `json:"runtime_group_id"`
+	Error          string          `json:"error"`
+}
+
+// CreateJob tracks a create of a runtime group the API answered with 202
+// Accepted, completing asynchronously. The Location header of the response
+// points either at the eventual runtime group, polled until it exists, or at
+// a job, polled until it succeeds or fails.
+type CreateJob struct {
+	c *Client
+	// location is the URL polled, nil when the create answered without one.
+	location *url.URL
+	// group is the created runtime group, nil while pending.
+	group *CreateRuntimeGroupResponse
+}
+
+// CreateRuntimeGroupAsync sends a POST request to create a runtime group and
+// returns without waiting for an asynchronous create, for callers polling it
+// themselves. A 202 Accepted response is a success whatever WithAcceptAny2xx,
+// other responses complete the job at once.
+func (c *Client) CreateRuntimeGroupAsync(ctx context.Context, requestBody CreateRuntimeGroupRequest) (*CreateJob, error) {
+	return c.createRuntimeGroup(ctx, requestBody, true)
+}
+
+// acceptedJob returns the job of a create to base answered with 202 Accepted.
+// Without a Location header, the response is read as the runtime group.
+func (c *Client) acceptedJob(ctx context.Context, base *url.URL, resp *http.Response) (*CreateJob, error) {
+	location := resp.Header.Get("Location")
+	if location == "" {
+		group, err := c.readRuntimeGroup(ctx, resp, "")
+		if err != nil {
+			return nil, err
+		}
+
+		return &CreateJob{c: c, group: group}, nil
+	}
+
+	// The location may be relative to the create request.
+	locationURL, err := base.Parse(location)
+	if err != nil {
+		return nil, c.wrap("parsing Location header", err)
+	}
+
+	return &CreateJob{c: c, location: locationURL}, nil
+}
+
+// Done reports whether the runtime group was created.
+func (j *CreateJob) Done() bool {
+	return j.group != nil
+}
+
+// Location returns the URL polled for the outcome of the create, empty when
+// the API answered without one.
+func (j *CreateJob) Location() string {
+	if j.location == nil {
+		return ""
+	}
+
+	return j.location.String()
+}
+
+// ID returns the id of the runtime group once created, or while pending when
+// the location points at the runtime group itself. It is empty otherwise.
+func (j *CreateJob) ID() string {
+	if j.group != nil {
+		return j.group.ID
+	}
+
+	if j.location != nil && runtimeGroupLocation(j.location) {
+		return path.Base(j.location.Path)
+	}
+
+	return ""
+}
+
+// Poll checks the location of the job once, returning the runtime group when
+// created and nil while the create is pending.
+func (j *CreateJob) Poll(ctx context.Context) (*CreateRuntimeGroupResponse, error) {
+	if j.group != nil {
+		return j.group, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.location.String(), nil)
+	if err != nil {
+		return nil, j.c.wrap("creating HTTP request", err)
+	}
+
+	resp, err := j.c.do(req)
+	if err != nil {
+		return nil, j.c.wrap("making HTTP request", err)
+	}
+	defer resp.Body.Close()
+
+	// Completed jobs may redirect to the runtime group.
+	polled := j.location
+	if resp.Request != nil {
+		polled = resp.Request.URL
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusAccepted:
+		return nil, nil
+	case resp.StatusCode == http.StatusNotFound && runtimeGroupLocation(polled):
+		// The runtime group is not visible yet.
+		return nil, nil
+	}
+
+	if err := j.c.checkResponse(resp); err != nil {
+		return nil, j.c.wrap("checking status code", err)
+	}
+
+	if runtimeGroupLocation(polled) {
+		j.group, err = j.c.readRuntimeGroup(ctx, resp, path.Base(polled.Path))
+		return j.group, err
+	}
+
+	return j.readJobStatus(ctx, resp)
+}
+
+// readJobStatus reads the job document of a poll, fetching the runtime group
+// once the job succeeded. Locations answering with the runtime group itself
+// are read as such.
+func (j *CreateJob) readJobStatus(ctx context.Context, resp *http.Response) (*CreateRuntimeGroupResponse, error) {
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, j.c.wrap("reading response body", err)
+	}
+
+	var job createJobStatus
+	if err := json.Unmarshal(raw, &job); err != nil {
+		return nil, j.c.wrap("decoding job status", err)
+	}
+
+	var status string
+	if json.Unmarshal(job.Status, &status) != nil {
+		resp.Body = io.NopCloser(bytes.NewReader(raw))
+		j.group, err = j.c.readRuntimeGroup(ctx, resp, "")
+		return j.group, err
+	}
+
+	switch strings.ToLower(status) {
+	case jobStatusPending, jobStatusRunning:
+		return nil, nil
+	case jobStatusFailed:
+		return nil, j.c.wrap("creating runtime group", fmt.Errorf("job %s failed: %s", j.location.Redacted(), job.Error))
+	case jobStatusSucceeded:
+		if job.RuntimeGroupID == "" {
+			return nil, j.c.wrap("reading job status", fmt.Errorf("job %s succeeded without a runtime group id", j.location.Redacted()))
+		}
+
+		j.group, err = j.c.GetRuntimeGroup(ctx, job.RuntimeGroupID)
+		return j.group, err
+	}
+
+	return nil, j.c.wrap("reading job status", fmt.Errorf("unknown status %q of job %s", status, j.location.Redacted()))
+}
+
+// Wait polls the location of the job until the runtime group is created, or
+// ctx is done. Without a deadline on ctx, it gives up after maxAcceptedPolls
+// polls.
+func (j *CreateJob) Wait(ctx context.Context) (*CreateRuntimeGroupResponse, error) {
+	_, bounded := ctx.Deadline()
+	for poll := 0; j.group == nil && (bounded || poll < maxAcceptedPolls); poll++ {
+		if err := j.c.clock.Sleep(ctx, acceptedPollInterval); err != nil {
+			return nil, j.c.wrap("waiting for the accepted create", err)
+		}
+
+		if _, err := j.Poll(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if j.group == nil {
+		return nil, j.c.wrap("waiting for the accepted create", fmt.Errorf("still pending after %d polls of %s", maxAcceptedPolls, j.location.Redacted()))
+	}
+
+	return j.group, nil
+}
+
+// runtimeGroupLocation reports whether u is the URL of a runtime group, i.e.
+// its path ends with /runtime-groups/{id}.
+func runtimeGroupLocation(u *url.URL) bool {
+	dir, id := path.Split(strings.TrimSuffix(u.Path, "/"))
+	return id != "" && strings.HasSuffix(strings.TrimSuffix(dir, "/"), runtimeGroupEndpoint)
+}
@@ -0,0 +1,274 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAcceptAny2xx(t *testing.T) {
+	tests := map[string]struct {
+		acceptAny2xx bool
+		location     string
+		body         string
+		wantErr      bool
+		wantPolls    int32
+	}{
+		"strict by default": {
+			location: "/operations/op-1",
+			wantErr:  true,
+		},
+		"polls the location": {
+			acceptAny2xx: true,
+			location:     "/operations/op-1",
+			wantPolls:    3,
+		},
+		"body without location": {
+			acceptAny2xx: true,
+			body:         `{"id":"rg-1","name":"test"}`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var polls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/runtime-groups":
+					if tt.location != "" {
+						w.Header().Set("Location", tt.location)
+					}
+					w.WriteHeader(http.StatusAccepted)
+					fmt.Fprint(w, tt.body)
+				case "/operations/op-1":
+					// Pending for the first two polls.
+					if atomic.AddInt32(&polls, 1) < 3 {
+						w.WriteHeader(http.StatusAccepted)
+						return
+					}
+					fmt.Fprint(w, `{"id":"rg-1","name":"test"}`)
+				default:
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			clk := &fakeClock{now: time.Now()}
+			c, err := New(server.URL, testToken(t), WithAcceptAny2xx(tt.acceptAny2xx), WithClock(clk))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			group, err := c.CreateRuntimeGroupWithContext(context.Background(), CreateRuntimeGroupRequest{Name: "test"})
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if group.ID != "rg-1" {
+				t.Fatalf("unexpected runtime group: %+v", group)
+			}
+
+			if polls != tt.wantPolls || len(clk.sleeps) != int(tt.wantPolls) {
+				t.Fatalf("expected %d polls, got %d after %d sleeps", tt.wantPolls, polls, len(clk.sleeps))
+			}
+		})
+	}
+}
+
+func TestAcceptAny2xxPollLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Location", "/operations/op-1")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	clk := &fakeClock{now: time.Now()}
+	c, err := New(server.URL, testToken(t), WithAcceptAny2xx(true), WithClock(clk))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := c.CreateRuntimeGroupWithContext(context.Background(), CreateRuntimeGroupRequest{Name: "test"}); err == nil {
+		t.Fatal("expected an error for a create pending forever")
+	}
+
+	if len(clk.sleeps) != maxAcceptedPolls {
+		t.Fatalf("expected %d polls, got %d", maxAcceptedPolls, len(clk.sleeps))
+	}
+}
+
+func TestAcceptAny2xxPollDeadline(t *testing.T) {
+	// The job succeeds past the poll limit, which a deadline on ctx lifts.
+	polls := int32(maxAcceptedPolls + 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost:
+			w.Header().Set("Location", "/jobs/job-1")
+			w.WriteHeader(http.StatusAccepted)
+		case r.URL.Path == "/jobs/job-1":
+			if atomic.AddInt32(&polls, -1) > 0 {
+				fmt.Fprint(w, `{"status":"running"}`)
+				return
+			}
+			fmt.Fprint(w, `{"status":"succeeded","runtime_group_id":"rg-1"}`)
+		case r.URL.Path == "/runtime-groups/rg-1":
+			fmt.Fprint(w, `{"id":"rg-1","name":"test"}`)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	clk := &fakeClock{now: time.Now()}
+	c, err := New(server.URL, testToken(t), WithAcceptAny2xx(true), WithClock(clk))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	group, err := c.CreateRuntimeGroupWithContext(ctx, CreateRuntimeGroupRequest{Name: "test"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if group.ID != "rg-1" {
+		t.Fatalf("unexpected runtime group: %+v", group)
+	}
+
+	if len(clk.sleeps) != maxAcceptedPolls+10 {
+		t.Fatalf("expected %d polls, got %d", maxAcceptedPolls+10, len(clk.sleeps))
+	}
+}
+
+func TestCreateRuntimeGroupAsync(t *testing.T) {
+	tests := map[string]struct {
+		location string
+		// polls are the successive responses of the location.
+		polls       []string
+		wantID      string
+		wantErr     bool
+		wantPending int
+	}{
+		"job succeeds": {
+			location:    "/jobs/job-1",
+			polls:       []string{`{"status":"pending"}`, `{"status":"running"}`, `{"status":"succeeded","runtime_group_id":"rg-1"}`},
+			wantPending: 2,
+		},
+		"job fails": {
+			location: "/jobs/job-1",
+			polls:    []string{`{"status":"running"}`, `{"status":"failed","error":"quota exceeded"}`},
+			wantErr:  true,
+		},
+		"runtime group location": {
+			location:    "/runtime-groups/rg-1",
+			polls:       []string{"", "", `{"id":"rg-1","name":"test"}`},
+			wantID:      "rg-1",
+			wantPending: 2,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var polls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodPost:
+					w.Header().Set("Location", tt.location)
+					w.WriteHeader(http.StatusAccepted)
+				case r.URL.Path == tt.location:
+					body := tt.polls[atomic.AddInt32(&polls, 1)-1]
+					if body == "" {
+						// The runtime group does not exist yet.
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					fmt.Fprint(w, body)
+				case r.URL.Path == "/runtime-groups/rg-1":
+					fmt.Fprint(w, `{"id":"rg-1","name":"test"}`)
+				default:
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			c, err := New(server.URL, testToken(t))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			job, err := c.CreateRuntimeGroupAsync(context.Background(), CreateRuntimeGroupRequest{Name: "test"})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if job.Done() || job.Location() != server.URL+tt.location || job.ID() != tt.wantID {
+				t.Fatalf("unexpected pending job at %s with id %q", job.Location(), job.ID())
+			}
+
+			// Poll by hand until the create completes.
+			var group *CreateRuntimeGroupResponse
+			pending := 0
+			for group == nil && err == nil && pending < len(tt.polls) {
+				if group, err = job.Poll(context.Background()); group == nil && err == nil {
+					pending++
+				}
+			}
+
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if group.ID != "rg-1" || !job.Done() || job.ID() != "rg-1" {
+				t.Fatalf("unexpected runtime group: %+v", group)
+			}
+
+			if pending != tt.wantPending {
+				t.Fatalf("expected %d pending polls, got %d", tt.wantPending, pending)
+			}
+		})
+	}
+}
+
+func TestCreateRuntimeGroupAsyncCompleted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":"rg-1","name":"test"}`)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	job, err := c.CreateRuntimeGroupAsync(context.Background(), CreateRuntimeGroupRequest{Name: "test"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !job.Done() || job.ID() != "rg-1" || job.Location() != "" {
+		t.Fatalf("expected a completed job, got id %q at %q", job.ID(), job.Location())
+	}
+
+	if group, err := job.Wait(context.Background()); err != nil || group.ID != "rg-1" {
+		t.Fatalf("unexpected result: %+v, %v", group, err)
+	}
+}
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+const (
+	// runtimeGroupArchiveEndpoint is the action of a runtime group archiving it.
+	runtimeGroupArchiveEndpoint = "archive"
+
+	// archiveRuntimeGroupMethod is the HTTP method for archiving a runtime group.
+	archiveRuntimeGroupMethod = http.MethodPost
+)
+
+// ArchiveRuntimeGroup sends a POST request to archive the runtime group with
+// the given id. Unlike DeleteRuntimeGroup, the runtime group is preserved by
+// the API. ErrNotFound or ErrNotSupported is returned when the API does not
+// expose the action.
+func (c *Client) ArchiveRuntimeGroup(ctx context.Context, id string) error {
+	defer c.locks.lock(id)()
+
+	c.cache.invalidate(id)
+	defer c.cache.invalidate(id)
+
+	endpoint, err := c.endpoint(OperationArchiveRuntimeGroup, runtimeGroupEndpoint, url.PathEscape(id), runtimeGroupArchiveEndpoint)
+	if err != nil {
+		return c.wrap("joining base URL and endpoint", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, archiveRuntimeGroupMethod, endpoint, nil)
+	if err != nil {
+		return c.wrap("creating HTTP request", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return c.wrap("making HTTP request", err)
+	}
+	defer resp.Body.Close()
+
+	// Check the HTTP response status code.
+	if err := c.checkResponse(resp); err != nil {
+		return c.wrap("checking status code", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,246 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// maxConcurrentGets bounds the requests GetRuntimeGroups and
+// GetRuntimeGroupStatuses send at once when the API cannot fetch several
+// runtime groups in a single request.
+const maxConcurrentGets = 8
+
+// readCache holds runtime groups by id, see WithReadCache. A nil readCache is
+// a disabled cache.
+type readCache struct {
+	mu     sync.Mutex
+	groups map[string]cacheEntry
+
+	// ttl is the lifetime of the entries, unlimited when zero.
+	ttl time.Duration
+	// clock dates the entries, set by New to the clock of the Client.
+	clock clock
+}
+
+// cacheEntry is a cached runtime group and the time it was cached at.
+type cacheEntry struct {
+	group    *CreateRuntimeGroupResponse
+	cachedAt time.Time
+}
+
+// get returns a copy of the cached runtime group with the given id, unless
+// it expired.
+func (rc *readCache) get(id string) (*CreateRuntimeGroupResponse, bool) {
+	if rc == nil {
+		return nil, false
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	entry, ok := rc.groups[id]
+	if !ok {
+		return nil, false
+	}
+
+	if rc.ttl > 0 && rc.clock.Now().Sub(entry.cachedAt) >= rc.ttl {
+		delete(rc.groups, id)
+		return nil, false
+	}
+
+	return copyRuntimeGroup(entry.group), true
+}
+
+// put caches a copy of group.
+func (rc *readCache) put(group *CreateRuntimeGroupResponse) {
+	if rc == nil {
+		return
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if rc.groups == nil {
+		rc.groups = make(map[string]cacheEntry)
+	}
+	rc.groups[group.ID] = cacheEntry{group: copyRuntimeGroup(group), cachedAt: rc.clock.Now()}
+}
+
+// invalidate drops the runtime group with the given id from the cache. The
+// requests changing a runtime group invalidate it both before and after
+// they are sent, so that what a concurrent read cached in between is dropped.
+func (rc *readCache) invalidate(id string) {
+	if rc == nil {
+		return
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	delete(rc.groups, id)
+}
+
+// copyRuntimeGroup returns a copy of group that shares none of its maps.
+func copyRuntimeGroup(group *CreateRuntimeGroupResponse) *CreateRuntimeGroupResponse {
+	copied := *group
+	if group.Labels != nil {
+		copied.Labels = copyLabels(group.Labels)
+	}
+	if group.EntityVersion != nil {
+		version := *group.EntityVersion
+		copied.EntityVersion = &version
+	}
+	if group.Status != nil {
+		status := *group.Status
+		copied.Status = &status
+	}
+
+	return &copied
+}
+
+// GetRuntimeGroups fetches the runtime groups with the given ids, keyed by id.
+// Runtime groups that do not exist are left out. The runtime groups are listed
+// in a single filtered request, falling back to concurrent GetRuntimeGroup
+// calls for the ids the list did not return, e.g. when the API does not
+// support filtering by id. Cached runtime groups are not fetched again.
+func (c *Client) GetRuntimeGroups(ctx context.Context, ids []string) (map[string]*CreateRuntimeGroupResponse, error) {
+	groups := make(map[string]*CreateRuntimeGroupResponse, len(ids))
+	wanted := make(map[string]bool, len(ids))
+
+	var missing []string
+	for _, id := range ids {
+		if group, ok := c.cache.get(id); ok {
+			groups[id] = group
+		} else if !wanted[id] {
+			missing = append(missing, id)
+		}
+		wanted[id] = true
+	}
+
+	if len(missing) == 0 {
+		return groups, nil
+	}
+
+	listResp, err := c.ListAllRuntimeGroups(ctx, ListOptions{IDs: missing})
+	if err != nil && !filterUnsupported(err) {
+		return nil, c.wrap("listing runtime groups by id", err)
+	}
+
+	if err == nil {
+		for i := range listResp.Data {
+			group := &listResp.Data[i]
+			if wanted[group.ID] {
+				groups[group.ID] = group
+				c.cache.put(group)
+			}
+		}
+	}
+
+	var unresolved []string
+	for _, id := range missing {
+		if _, ok := groups[id]; !ok {
+			unresolved = append(unresolved, id)
+		}
+	}
+
+	fetched, err := c.getRuntimeGroupsConcurrently(ctx, unresolved)
+	if err != nil {
+		return nil, err
+	}
+
+	for id, group := range fetched {
+		groups[id] = group
+	}
+
+	return groups, nil
+}
+
+// filterUnsupported reports whether a filtered list failed because the API
+// does not support the filter.
+func filterUnsupported(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest {
+		return true
+	}
+
+	return errors.Is(err, ErrNotSupported)
+}
+
+// getRuntimeGroupsConcurrently calls GetRuntimeGroup for every id, at most
+// maxConcurrentGets at once, leaving out the runtime groups that do not exist.
+// It returns promptly with the context error once ctx is done.
+func (c *Client) getRuntimeGroupsConcurrently(ctx context.Context, ids []string) (map[string]*CreateRuntimeGroupResponse, error) {
+	var mu sync.Mutex
+	groups := make(map[string]*CreateRuntimeGroupResponse, len(ids))
+
+	err := c.fetchConcurrently(ctx, ids, "runtime group", func(id string) error {
+		group, err := c.GetRuntimeGroup(ctx, id)
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		groups[id] = group
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// fetchConcurrently calls fetch for every id, at most maxConcurrentGets at
+// once, returning the first error, if any, naming what is fetched. Queued ids
+// are not fetched once ctx is done, the requests in flight abort with it.
+func (c *Client) fetchConcurrently(ctx context.Context, ids []string, what string, fetch func(id string) error) error {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+
+	sem := make(chan struct{}, maxConcurrentGets)
+queue:
+	for _, id := range ids {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			mu.Lock()
+			if firstErr == nil {
+				firstErr = c.wrap("waiting to fetch "+what+" "+id, ctx.Err())
+			}
+			mu.Unlock()
+
+			break queue
+		}
+
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := fetch(id)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil && firstErr == nil {
+				firstErr = c.wrap("fetching "+what+" "+id, err)
+			}
+		}(id)
+	}
+
+	wg.Wait()
+
+	return firstErr
+}
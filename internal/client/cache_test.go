@@ -0,0 +1,331 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetRuntimeGroupsBatch(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		if r.URL.Path != "/runtime-groups" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+
+		if got := r.URL.Query().Get("filter[id]"); got != "a,b,missing" {
+			t.Errorf("expected filter[id]=a,b,missing, got %q", got)
+		}
+
+		fmt.Fprint(w, `{"meta":{"page":{"number":1,"size":10,"total":2}},"data":[{"id":"a","name":"one"},{"id":"b","name":"two"}]}`)
+	}))
+	defer server.Close()
+
+	// The missing runtime group is looked up on its own after the batch.
+	c, err := New(server.URL, testToken(t), WithEndpointOverrides(map[string]string{
+		OperationGetRuntimeGroup: notFoundServer(t).URL,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	groups, err := c.GetRuntimeGroups(context.Background(), []string{"a", "b", "missing", "a"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(groups) != 2 || groups["a"].Name != "one" || groups["b"].Name != "two" {
+		t.Fatalf("unexpected runtime groups: %v", groups)
+	}
+
+	if requests != 1 {
+		t.Fatalf("expected a single list request, got %d", requests)
+	}
+}
+
+func TestGetRuntimeGroupsConcurrentFallback(t *testing.T) {
+	var mu sync.Mutex
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+
+		if r.URL.Path == "/runtime-groups" {
+			// The API does not support filtering by id.
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		id := strings.TrimPrefix(r.URL.Path, "/runtime-groups/")
+		fmt.Fprintf(w, `{"id":%q,"name":"group-%s"}`, id, id)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	groups, err := c.GetRuntimeGroups(context.Background(), []string{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(groups) != 3 || groups["c"].Name != "group-c" {
+		t.Fatalf("unexpected runtime groups: %v", groups)
+	}
+
+	sort.Strings(paths)
+	if want := "/runtime-groups,/runtime-groups/a,/runtime-groups/b,/runtime-groups/c"; strings.Join(paths, ",") != want {
+		t.Fatalf("expected requests %s, got %s", want, strings.Join(paths, ","))
+	}
+}
+
+func TestGetRuntimeGroupsConcurrentCancel(t *testing.T) {
+	done := make(chan struct{})
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/runtime-groups" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		atomic.AddInt32(&requests, 1)
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(done)
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ids := make([]string, 3*maxConcurrentGets)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("rg-%d", i)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		_, err := c.GetRuntimeGroups(ctx, ids)
+		errs <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected GetRuntimeGroups to return on cancellation")
+	}
+
+	if n := atomic.LoadInt32(&requests); n > maxConcurrentGets {
+		t.Fatalf("expected the queued ids not to be fetched, got %d requests", n)
+	}
+}
+
+func TestReadCache(t *testing.T) {
+	var gets int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			gets++
+			fmt.Fprint(w, `{"id":"rg-1","name":"test","labels":{"env":"test"}}`)
+		case http.MethodPatch:
+			fmt.Fprint(w, `{"id":"rg-1","name":"renamed"}`)
+		}
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t), WithReadCache(true))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		group, err := c.GetRuntimeGroup(ctx, "rg-1")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		// Callers may modify the runtime group without corrupting the cache.
+		group.Labels["env"] = "modified"
+	}
+
+	if gets != 1 {
+		t.Fatalf("expected the second read to hit the cache, got %d requests", gets)
+	}
+
+	groups, err := c.GetRuntimeGroups(ctx, []string{"rg-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if gets != 1 || groups["rg-1"].Labels["env"] != "test" {
+		t.Fatalf("expected an unmodified cache hit, got %v after %d requests", groups["rg-1"], gets)
+	}
+
+	if _, err := c.UpdateRuntimeGroup(ctx, "rg-1", UpdateRuntimeGroupRequest{Name: "renamed"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := c.GetRuntimeGroup(ctx, "rg-1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if gets != 2 {
+		t.Fatalf("expected the update to invalidate the cache, got %d requests", gets)
+	}
+}
+
+func TestReadCacheConcurrentUpdate(t *testing.T) {
+	// patching is closed once the update reached the server, which holds it
+	// until read is closed.
+	patching, read := make(chan struct{}), make(chan struct{})
+	var name atomic.Value
+	name.Store("test")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprintf(w, `{"id":"rg-1","name":%q}`, name.Load())
+		case http.MethodPatch:
+			close(patching)
+			<-read
+			name.Store("renamed")
+			fmt.Fprint(w, `{"id":"rg-1","name":"renamed"}`)
+		}
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t), WithReadCache(true))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ctx := context.Background()
+	updated := make(chan error)
+	go func() {
+		_, err := c.UpdateRuntimeGroup(ctx, "rg-1", UpdateRuntimeGroupRequest{Name: "renamed"})
+		updated <- err
+	}()
+
+	// A read during the update caches the runtime group before the update.
+	<-patching
+	if group, err := c.GetRuntimeGroup(ctx, "rg-1"); err != nil || group.Name != "test" {
+		t.Fatalf("unexpected runtime group %+v, error %v", group, err)
+	}
+	close(read)
+
+	if err := <-updated; err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	group, err := c.GetRuntimeGroup(ctx, "rg-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if group.Name != "renamed" {
+		t.Fatalf("expected the update to drop the runtime group read meanwhile, got %q", group.Name)
+	}
+}
+
+func TestReadCacheTTL(t *testing.T) {
+	var gets int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			gets++
+			fmt.Fprint(w, `{"id":"rg-1","name":"test"}`)
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	clk := &fakeClock{now: time.Now()}
+	c, err := New(server.URL, testToken(t), WithReadCacheTTL(time.Minute), WithClock(clk))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ctx := context.Background()
+	read := func(wantGets int, when string) {
+		t.Helper()
+
+		if _, err := c.GetRuntimeGroup(ctx, "rg-1"); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if gets != wantGets {
+			t.Fatalf("expected %d requests %s, got %d", wantGets, when, gets)
+		}
+	}
+
+	read(1, "on the first read")
+	clk.Sleep(ctx, 59*time.Second)
+	read(1, "before the TTL elapsed")
+	clk.Sleep(ctx, time.Second)
+	read(2, "once the TTL elapsed")
+
+	if err := c.DeleteRuntimeGroup(ctx, "rg-1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	read(3, "after a delete")
+}
+
+func TestReadCacheDisabledByDefault(t *testing.T) {
+	var gets int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gets++
+		fmt.Fprint(w, `{"id":"rg-1"}`)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.GetRuntimeGroup(context.Background(), "rg-1"); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	if gets != 2 {
+		t.Fatalf("expected no caching, got %d requests", gets)
+	}
+}
+
+// notFoundServer returns a server answering every request with 404 Not Found.
+func notFoundServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(server.Close)
+
+	return server
+}
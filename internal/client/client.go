@@ -2,32 +2,215 @@ package client
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"github.com/golang-jwt/jwt"
+	"github.com/google/uuid"
+	"io"
+	"mime"
+	"net"
 	"net/http"
 	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const (
 	// endpoints
 	// runtimeGroupEndpoint is the endpoint for operations with a runtime group.
-	runtimeGroupEndpoint = "/create-runtime-group"
+	runtimeGroupEndpoint = "/runtime-groups"
+
+	// defaultFallbackTimeout bounds the requests whose context has no deadline,
+	// see WithContextTimeout.
+	defaultFallbackTimeout = 60 * time.Second
+
+	// methodOverrideHeader carries the real method of the requests sent as POST,
+	// see WithMethodOverride.
+	methodOverrideHeader = "X-HTTP-Method-Override"
+
+	// idempotencyKeyHeader lets the API deduplicate resent create requests.
+	idempotencyKeyHeader = "Idempotency-Key"
+
+	// mergePatchContentType is the Content-Type of updates, see WithMergePatch.
+	mergePatchContentType = "application/merge-patch+json"
 
 	// methods
 	// createRuntimeGroupMethod is the HTTP method for creating a runtime group.
 	createRuntimeGroupMethod = http.MethodPost
+	// listRuntimeGroupsMethod is the HTTP method for listing runtime groups.
+	listRuntimeGroupsMethod = http.MethodGet
+	// getRuntimeGroupMethod is the HTTP method for fetching a runtime group.
+	getRuntimeGroupMethod = http.MethodGet
+	// updateRuntimeGroupMethod is the HTTP method for updating a runtime group.
+	updateRuntimeGroupMethod = http.MethodPatch
+	// deleteRuntimeGroupMethod is the HTTP method for deleting a runtime group.
+	deleteRuntimeGroupMethod = http.MethodDelete
+
+	// operations
+	// OperationCreateRuntimeGroup names CreateRuntimeGroup in WithEndpointOverrides.
+	OperationCreateRuntimeGroup = "create_runtime_group"
+	// OperationListRuntimeGroups names ListRuntimeGroups in WithEndpointOverrides.
+	OperationListRuntimeGroups = "list_runtime_groups"
+	// OperationGetRuntimeGroup names GetRuntimeGroup in WithEndpointOverrides.
+	OperationGetRuntimeGroup = "get_runtime_group"
+	// OperationUpdateRuntimeGroup names UpdateRuntimeGroup in WithEndpointOverrides.
+	OperationUpdateRuntimeGroup = "update_runtime_group"
+	// OperationDeleteRuntimeGroup names DeleteRuntimeGroup in WithEndpointOverrides.
+	OperationDeleteRuntimeGroup = "delete_runtime_group"
+	// OperationGetRuntimeGroupStatus names GetRuntimeGroupStatus in WithEndpointOverrides.
+	OperationGetRuntimeGroupStatus = "get_runtime_group_status"
+	// OperationRotateRuntimeGroupCredentials names RotateRuntimeGroupCredentials in WithEndpointOverrides.
+	OperationRotateRuntimeGroupCredentials = "rotate_runtime_group_credentials"
+	// OperationArchiveRuntimeGroup names ArchiveRuntimeGroup in WithEndpointOverrides.
+	OperationArchiveRuntimeGroup = "archive_runtime_group"
+	// OperationReplaceRuntimeGroup names ReplaceRuntimeGroup in WithEndpointOverrides.
+	OperationReplaceRuntimeGroup = "replace_runtime_group"
+	// OperationTransferRuntimeGroup names TransferRuntimeGroup in WithEndpointOverrides.
+	OperationTransferRuntimeGroup = "transfer_runtime_group"
+	// OperationUpdateRuntimeGroupLabels names UpdateRuntimeGroupLabels in WithEndpointOverrides.
+	OperationUpdateRuntimeGroupLabels = "update_runtime_group_labels"
+	// OperationPatchRuntimeGroup names PatchRuntimeGroup in WithEndpointOverrides.
+	OperationPatchRuntimeGroup = "patch_runtime_group"
 )
 
+// Operations lists the operation names accepted by WithEndpointOverrides.
+func Operations() []string {
+	return []string{
+		OperationCreateRuntimeGroup,
+		OperationListRuntimeGroups,
+		OperationGetRuntimeGroup,
+		OperationUpdateRuntimeGroup,
+		OperationDeleteRuntimeGroup,
+		OperationGetRuntimeGroupStatus,
+		OperationRotateRuntimeGroupCredentials,
+		OperationArchiveRuntimeGroup,
+		OperationReplaceRuntimeGroup,
+		OperationTransferRuntimeGroup,
+		OperationUpdateRuntimeGroupLabels,
+		OperationPatchRuntimeGroup,
+	}
+}
+
 // Client is the representation of http client for the GroupAPI.
 type Client struct {
 	BaseUrl string
-	token   string
+
+	// tokenMu guards token, which may be rotated while requests are in flight.
+	tokenMu     sync.Mutex
+	token       string
+	tokenSource TokenSource
+
+	// basePath is joined between BaseUrl and every endpoint.
+	basePath string
+	// endpointOverrides replaces BaseUrl for the operations it names.
+	endpointOverrides map[string]string
+	// defaultLabels are merged into the labels of every created runtime group.
+	defaultLabels map[string]string
+	// defaultClusterType is the cluster type of the runtime groups created
+	// without one by the provider, see WithDefaultClusterType.
+	defaultClusterType ClusterType
+	// skipTokenValidation bypasses the validation of the token in New.
+	skipTokenValidation bool
+
+	// authScheme is the scheme of the Authorization header, "Bearer" when empty.
+	authScheme string
+	// rawAuthHeader replaces the whole Authorization header when set.
+	rawAuthHeader string
+
+	// maxRetries is the number of retries of transient failures, none by default.
+	maxRetries int
+	// retryBackoff is the base delay of the retries, paced by backoff.
+	retryBackoff time.Duration
+	backoff      BackoffStrategy
+
+	// clock drives the retry backoff and the token expiry checks.
+	clock clock
+
+	// interceptors mutate every request after the built-in headers are set.
+	interceptors []RequestInterceptor
+	// responseValidators check every decoded response.
+	responseValidators []ResponseValidator
+
+	// fallbackTimeout bounds the requests whose context has no deadline.
+	fallbackTimeout time.Duration
+	// requestTimeout bounds every request, whatever its context.
+	requestTimeout time.Duration
+	// baseCtx aborts every request once done, nil when unset.
+	baseCtx context.Context
+
+	// forceHTTP1 disables HTTP/2, disableKeepAlives disables connection reuse,
+	// disableCompression disables the transparent gzip of the transport.
+	forceHTTP1         bool
+	disableKeepAlives  bool
+	disableCompression bool
+	// dialContext opens the connections of the transport, nil for the default
+	// dialer.
+	dialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+	// roundTripperWrappers wrap the transport, the first one is outermost.
+	roundTripperWrappers []RoundTripperWrapper
+	// httpClient performs the requests, built in New from the options.
+	httpClient *http.Client
+
+	// cache holds the runtime groups read by id, nil when disabled.
+	cache *readCache
+	// locks serializes the mutating operations on the same runtime group, nil
+	// when disabled.
+	locks *groupLocks
+	// etags holds the runtime groups read by id with their ETag, nil when
+	// disabled.
+	etags *etagCache
+
+	// preferMinimal asks for minimal responses to mutating requests.
+	preferMinimal bool
+
+	// tracer starts a span per request, nil when tracing is disabled.
+	tracer Tracer
+	// metrics records every request, nil when disabled.
+	metrics MetricsRecorder
+
+	// slots caps the requests in flight, nil when unlimited.
+	slots chan struct{}
+
+	// methodOverride sends mutations as POST, see WithMethodOverride.
+	methodOverride bool
+
+	// mergePatch sends updates as JSON Merge Patch documents.
+	mergePatch bool
+
+	// acceptAny2xx treats every 2xx status code as a success, see
+	// WithAcceptAny2xx.
+	acceptAny2xx bool
+
+	// noLabelsEndpoint records that the API has no labels subresource, see
+	// UpdateRuntimeGroupLabels.
+	noLabelsEndpoint atomic.Bool
+
+	// maxBodyLogBytes bounds the bodies logged at trace level, zero omits them.
+	maxBodyLogBytes int
+
+	// idempotencyKey computes the idempotency key of create requests.
+	idempotencyKey func(CreateRuntimeGroupRequest) string
+
+	// encoder serializes the request bodies, see WithRequestEncoder.
+	encoder RequestEncoder
 }
 
 // New is a constructor for Client.
-func New(baseULR, token string) (*Client, error) {
-	client := &Client{}
+func New(baseULR, token string, opts ...Option) (*Client, error) {
+	client := &Client{
+		clock:           realClock{},
+		fallbackTimeout: defaultFallbackTimeout,
+		maxBodyLogBytes: defaultMaxBodyLogBytes,
+		idempotencyKey:  randomIdempotencyKey,
+		encoder:         encodeJSON,
+		backoff:         ExponentialBackoff{Jitter: true},
+	}
 
 	// baseULR validation.
 	_, err := url.Parse(baseULR)
@@ -35,113 +218,932 @@ func New(baseULR, token string) (*Client, error) {
 		return nil, client.wrap("error parsing base URL", err)
 	}
 
-	// token validation.
-	if err := validateBearerToken(token); err != nil {
-		return nil, client.wrap("error validating bearer token", err)
-	}
-
 	client.BaseUrl = baseULR
 	client.token = token
 
+	for _, opt := range opts {
+		if err := opt(client); err != nil {
+			return nil, client.wrap("applying option", err)
+		}
+	}
+
+	client.httpClient = &http.Client{Transport: client.roundTripper()}
+
+	if client.cache != nil {
+		client.cache.clock = client.clock
+	}
+
+	if client.authScheme != "" && client.rawAuthHeader != "" {
+		return nil, client.wrap("applying option", fmt.Errorf("auth scheme and raw auth header are mutually exclusive"))
+	}
+
+	// token validation. The token may be left empty when a token source
+	// supplies it on the first request, or when a raw auth header replaces it.
+	if !client.skipTokenValidation && client.rawAuthHeader == "" && (token != "" || client.tokenSource == nil) {
+		if err := validateBearerToken(token, client.clock.Now()); err != nil {
+			return nil, client.wrap("error validating bearer token", err)
+		}
+	}
+
 	return client, nil
 }
 
 // CreateRuntimeGroupRequest represents the request body for creating a runtime group.
 type CreateRuntimeGroupRequest struct {
-	Name        string            `json:"name"`
-	Description string            `json:"description"`
-	ClusterType string            `json:"cluster_type"`
-	Labels      map[string]string `json:"labels"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	ClusterType ClusterType `json:"cluster_type"`
+	// Labels is omitted from the payload when nil and sent as {} when empty,
+	// see labelsField.
+	Labels map[string]string `json:"labels"`
+}
+
+// MarshalJSON omits nil labels while keeping empty ones.
+func (r CreateRuntimeGroupRequest) MarshalJSON() ([]byte, error) {
+	type alias CreateRuntimeGroupRequest
+
+	return json.Marshal(struct {
+		alias
+		Labels *map[string]string `json:"labels,omitempty"`
+	}{alias(r), labelsField(r.Labels)})
+}
+
+// UpdateRuntimeGroupRequest represents the request body for updating a runtime group.
+type UpdateRuntimeGroupRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Labels is omitted from the payload when nil, leaving the labels of the
+	// runtime group untouched, and sent as {} when empty, removing them all.
+	Labels map[string]string `json:"labels"`
+	// PriorLabels are the labels before the update. With WithMergePatch, only
+	// the labels changed from them are sent, and the removed ones as null.
+	PriorLabels map[string]string `json:"-"`
+	// ClusterType is the unchanged cluster type of the runtime group. It is not
+	// sent, only $cluster_type in the default labels is expanded to it.
+	ClusterType ClusterType `json:"-"`
+}
+
+// MarshalJSON omits nil labels while keeping empty ones.
+func (r UpdateRuntimeGroupRequest) MarshalJSON() ([]byte, error) {
+	type alias UpdateRuntimeGroupRequest
+
+	return json.Marshal(struct {
+		alias
+		Labels *map[string]string `json:"labels,omitempty"`
+	}{alias(r), labelsField(r.Labels)})
+}
+
+// mergePatch returns the JSON Merge Patch (RFC 7396) body of the request. The
+// labels object only holds the labels changed from PriorLabels, the removed
+// ones set to null, and is omitted when none changed.
+func (r UpdateRuntimeGroupRequest) mergePatch() ([]byte, error) {
+	var labels map[string]*string
+	if r.Labels != nil {
+		labels = make(map[string]*string)
+		for k, v := range r.Labels {
+			if prior, ok := r.PriorLabels[k]; !ok || prior != v {
+				v := v
+				labels[k] = &v
+			}
+		}
+
+		for k := range r.PriorLabels {
+			if _, ok := r.Labels[k]; !ok {
+				labels[k] = nil
+			}
+		}
+	}
+
+	return json.Marshal(struct {
+		Name        string             `json:"name"`
+		Description string             `json:"description"`
+		Labels      map[string]*string `json:"labels,omitempty"`
+	}{r.Name, r.Description, labels})
+}
+
+// labelsField distinguishes labels that must not be touched (nil, omitted from
+// the payload) from labels that must be cleared (empty, sent as {}).
+func labelsField(labels map[string]string) *map[string]string {
+	if labels == nil {
+		return nil
+	}
+
+	return &labels
+}
+
+// ClusterType is the type of the cluster associated with a runtime group.
+type ClusterType string
+
+// The cluster types accepted by the API.
+const (
+	ClusterTypeHybrid               ClusterType = "CLUSTER_TYPE_HYBRID"
+	ClusterTypeK8sIngressController ClusterType = "CLUSTER_TYPE_K8S_INGRESS_CONTROLLER"
+	ClusterTypeComposite            ClusterType = "CLUSTER_TYPE_COMPOSITE"
+)
+
+// clusterTypes are the cluster types accepted by the API.
+var clusterTypes = []ClusterType{
+	ClusterTypeHybrid,
+	ClusterTypeK8sIngressController,
+	ClusterTypeComposite,
+}
+
+// Valid reports whether t is one of the cluster types accepted by the API.
+func (t ClusterType) Valid() bool {
+	for _, clusterType := range clusterTypes {
+		if t == clusterType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DefaultClusterType returns the cluster type set by WithDefaultClusterType,
+// empty when none is set.
+func (c *Client) DefaultClusterType() ClusterType {
+	return c.defaultClusterType
+}
+
+// ClusterTypes lists the cluster types accepted by the API, e.g. for schema
+// validators.
+func ClusterTypes() []string {
+	values := make([]string, len(clusterTypes))
+	for i, clusterType := range clusterTypes {
+		values[i] = string(clusterType)
+	}
+
+	return values
+}
+
+// Validate checks the required fields and the label keys of the request before
+// it is sent.
+func (r CreateRuntimeGroupRequest) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	if err := validateLabels(r.Labels); err != nil {
+		return err
+	}
+
+	// ClusterType is optional, the API picks its default when it is empty.
+	if r.ClusterType == "" || r.ClusterType.Valid() {
+		return nil
+	}
+
+	return fmt.Errorf("cluster type %q is not one of %v", r.ClusterType, clusterTypes)
+}
+
+// RuntimeGroupConfig represents the config object of a runtime group.
+type RuntimeGroupConfig struct {
+	ControlPlaneEndpoint string `json:"control_plane_endpoint"`
+	TelemetryEndpoint    string `json:"telemetry_endpoint"`
+	// ClusterType is empty when the API does not return it.
+	ClusterType ClusterType `json:"cluster_type,omitempty"`
 }
 
 // CreateRuntimeGroupResponse represents the response from creating a runtime group.
 type CreateRuntimeGroupResponse struct {
-	ID          string            `json:"id"`
-	Name        string            `json:"name"`
-	Description string            `json:"description"`
-	Labels      map[string]string `json:"labels"`
-	Config      struct {
-		ControlPlaneEndpoint string `json:"control_plane_endpoint"`
-		TelemetryEndpoint    string `json:"telemetry_endpoint"`
-	} `json:"config"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	ID          string             `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Labels      map[string]string  `json:"labels"`
+	Config      RuntimeGroupConfig `json:"config"`
+	CreatedAt   string             `json:"created_at"`
+	UpdatedAt   string             `json:"updated_at"`
+	// EntityVersion is incremented on every change of the runtime group, e.g.
+	// for optimistic concurrency. It is nil when the API does not return it.
+	EntityVersion *int64 `json:"entity_version,omitempty"`
+	// Status is the data plane connectivity status, only returned when asked
+	// for with IncludeStatus and supported by the API.
+	Status *RuntimeGroupStatus `json:"status,omitempty"`
+
+	// Raw is the response body as returned by the API, including the fields
+	// not modelled above.
+	Raw json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the runtime group and keeps data as its Raw response,
+// also for the runtime groups of a list.
+func (r *CreateRuntimeGroupResponse) UnmarshalJSON(data []byte) error {
+	type alias CreateRuntimeGroupResponse
+	if err := json.Unmarshal(data, (*alias)(r)); err != nil {
+		return err
+	}
+
+	r.Raw = append(json.RawMessage(nil), data...)
+
+	return nil
 }
 
 // CreateRuntimeGroup sends a POST request to create a runtime group.
 func (c *Client) CreateRuntimeGroup(requestBody CreateRuntimeGroupRequest) (*CreateRuntimeGroupResponse, error) {
-	requestBodyBytes, err := json.Marshal(requestBody)
+	return c.CreateRuntimeGroupWithContext(context.Background(), requestBody)
+}
+
+// CreateRuntimeGroupWithContext is CreateRuntimeGroup with a context bounding
+// the request.
+func (c *Client) CreateRuntimeGroupWithContext(ctx context.Context, requestBody CreateRuntimeGroupRequest) (*CreateRuntimeGroupResponse, error) {
+	// 202 Accepted is only a success with WithAcceptAny2xx.
+	job, err := c.createRuntimeGroup(ctx, requestBody, c.acceptAny2xx)
+	if err != nil {
+		return nil, err
+	}
+
+	return job.Wait(ctx)
+}
+
+// createRuntimeGroup sends a POST request to create a runtime group. A 202
+// Accepted response is tracked by the returned job when accepted is true, and
+// an error otherwise.
+func (c *Client) createRuntimeGroup(ctx context.Context, requestBody CreateRuntimeGroupRequest, accepted bool) (*CreateJob, error) {
+	if err := requestBody.Validate(); err != nil {
+		return nil, c.wrap("validating request body", err)
+	}
+
+	requestBody.Labels = c.mergeDefaultLabels(requestBody.Labels, labelVars{Name: requestBody.Name, ClusterType: requestBody.ClusterType})
+
+	requestBodyBytes, contentType, err := c.encoder(requestBody)
 	if err != nil {
 		return nil, c.wrap(" serializing request body", err)
 	}
 
-	endpoint, err := url.JoinPath(c.BaseUrl, runtimeGroupEndpoint)
+	endpoint, err := c.endpoint(OperationCreateRuntimeGroup, runtimeGroupEndpoint)
 	if err != nil {
 		return nil, c.wrap(" joining base URL and endpoint", err)
 	}
 
-	req, err := http.NewRequest(createRuntimeGroupMethod, endpoint, bytes.NewBuffer(requestBodyBytes))
+	req, err := http.NewRequestWithContext(ctx, createRuntimeGroupMethod, endpoint, bytes.NewBuffer(requestBodyBytes))
+	if err != nil {
+		return nil, c.wrap("creating HTTP request", err)
+	}
+
+	req.Header.Set("Content-Type", contentType)
+
+	// The key is kept across retries, so a create that reached the API before
+	// the connection failed is not repeated.
+	req.Header.Set(idempotencyKeyHeader, c.idempotencyKey(requestBody))
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, c.wrap("making HTTP request", err)
+	}
+	defer resp.Body.Close()
+
+	if accepted && resp.StatusCode == http.StatusAccepted {
+		return c.acceptedJob(req.Context(), req.URL, resp)
+	}
+
+	// Check the HTTP response status code.
+	if err := c.checkResponse(resp); err != nil {
+		return nil, c.wrap("checking status code", err)
+	}
+
+	group, err := c.readRuntimeGroup(req.Context(), resp, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &CreateJob{c: c, group: group}, nil
+}
+
+// encodeJSON is the default RequestEncoder, serializing bodies as JSON.
+func encodeJSON(body any) ([]byte, string, error) {
+	encoded, err := json.Marshal(body)
+	return encoded, "application/json", err
+}
+
+// randomIdempotencyKey is the default idempotency key, a random UUID.
+func randomIdempotencyKey(CreateRuntimeGroupRequest) string {
+	return uuid.NewString()
+}
+
+// GetRuntimeGroup sends a GET request to fetch the runtime group with the given id.
+// ErrNotFound is returned when the runtime group does not exist. The request is
+// skipped when the read cache holds the runtime group, see WithReadCache.
+func (c *Client) GetRuntimeGroup(ctx context.Context, id string) (*CreateRuntimeGroupResponse, error) {
+	return c.GetRuntimeGroupWithOptions(ctx, id, GetOptions{})
+}
+
+// GetRuntimeGroupWithOptions is GetRuntimeGroup with the related objects in
+// opts.Include expanded in the response. The read cache only answers when the
+// cached runtime group holds them, and never stores the partial runtime groups
+// restricted by opts.Fields.
+func (c *Client) GetRuntimeGroupWithOptions(ctx context.Context, id string, opts GetOptions) (*CreateRuntimeGroupResponse, error) {
+	if group, ok := c.cache.get(id); ok && opts.includedIn(group) {
+		return group, nil
+	}
+
+	endpoint, err := c.endpoint(OperationGetRuntimeGroup, runtimeGroupEndpoint, url.PathEscape(id))
+	if err != nil {
+		return nil, c.wrap("joining base URL and endpoint", err)
+	}
+
+	query := opts.values().Encode()
+	if query != "" {
+		endpoint += "?" + query
+	}
+
+	req, err := http.NewRequestWithContext(ctx, getRuntimeGroupMethod, endpoint, nil)
 	if err != nil {
 		return nil, c.wrap("creating HTTP request", err)
 	}
 
+	cached, conditional := c.etags.get(id, query)
+	if conditional {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
 	resp, err := c.do(req)
 	if err != nil {
 		return nil, c.wrap("making HTTP request", err)
 	}
 	defer resp.Body.Close()
 
+	// The runtime group did not change since it was cached.
+	if conditional && resp.StatusCode == http.StatusNotModified {
+		if len(opts.Fields) == 0 {
+			c.cache.put(cached.group)
+		}
+		return cached.group, nil
+	}
+
 	// Check the HTTP response status code.
-	if err := c.codeToErr(resp.StatusCode); err != nil {
+	if err := c.checkResponse(resp); err != nil {
 		return nil, c.wrap("checking status code", err)
 	}
 
-	var createResponse CreateRuntimeGroupResponse
-	if err := json.NewDecoder(resp.Body).Decode(&createResponse); err != nil {
+	var getResponse CreateRuntimeGroupResponse
+	if getResponse.Raw, err = readJSON(resp.Body, &getResponse); err != nil {
 		return nil, c.wrap("decoding response JSON", err)
 	}
 
-	return &createResponse, nil
+	if err := c.validateResponse(&getResponse); err != nil {
+		return nil, err
+	}
+
+	if len(opts.Fields) == 0 {
+		c.cache.put(&getResponse)
+	}
+	c.etags.put(query, resp.Header.Get("ETag"), &getResponse)
+
+	return &getResponse, nil
+}
+
+// UpdateRuntimeGroup sends a PATCH request to update the runtime group with the given id.
+// Fields of the request are sent as is, except nil labels which are left untouched.
+func (c *Client) UpdateRuntimeGroup(ctx context.Context, id string, requestBody UpdateRuntimeGroupRequest) (*CreateRuntimeGroupResponse, error) {
+	defer c.locks.lock(id)()
+
+	return c.updateRuntimeGroup(ctx, id, requestBody)
+}
+
+// updateRuntimeGroup is UpdateRuntimeGroup for callers already holding the lock
+// of the runtime group.
+func (c *Client) updateRuntimeGroup(ctx context.Context, id string, requestBody UpdateRuntimeGroupRequest) (*CreateRuntimeGroupResponse, error) {
+	c.cache.invalidate(id)
+	defer c.cache.invalidate(id)
+
+	if err := validateLabels(requestBody.Labels); err != nil {
+		return nil, c.wrap("validating request body", err)
+	}
+
+	if requestBody.Labels != nil {
+		requestBody.Labels = c.mergeDefaultLabels(requestBody.Labels, labelVars{Name: requestBody.Name, ClusterType: requestBody.ClusterType})
+	}
+
+	encode := c.encoder
+	if c.mergePatch {
+		encode = func(any) ([]byte, string, error) {
+			body, err := requestBody.mergePatch()
+			return body, mergePatchContentType, err
+		}
+	}
+
+	requestBodyBytes, contentType, err := encode(requestBody)
+	if err != nil {
+		return nil, c.wrap("serializing request body", err)
+	}
+
+	endpoint, err := c.endpoint(OperationUpdateRuntimeGroup, runtimeGroupEndpoint, url.PathEscape(id))
+	if err != nil {
+		return nil, c.wrap("joining base URL and endpoint", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, updateRuntimeGroupMethod, endpoint, bytes.NewBuffer(requestBodyBytes))
+	if err != nil {
+		return nil, c.wrap("creating HTTP request", err)
+	}
+
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, c.wrap("making HTTP request", err)
+	}
+	defer resp.Body.Close()
+
+	// Check the HTTP response status code.
+	if err := c.checkResponse(resp); err != nil {
+		return nil, c.wrap("checking status code", err)
+	}
+
+	return c.readRuntimeGroup(req.Context(), resp, id)
+}
+
+// DeleteRuntimeGroup sends a DELETE request to delete the runtime group with the given id.
+func (c *Client) DeleteRuntimeGroup(ctx context.Context, id string) error {
+	defer c.locks.lock(id)()
+
+	c.cache.invalidate(id)
+	defer c.cache.invalidate(id)
+
+	endpoint, err := c.endpoint(OperationDeleteRuntimeGroup, runtimeGroupEndpoint, url.PathEscape(id))
+	if err != nil {
+		return c.wrap("joining base URL and endpoint", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, deleteRuntimeGroupMethod, endpoint, nil)
+	if err != nil {
+		return c.wrap("creating HTTP request", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return c.wrap("making HTTP request", err)
+	}
+	defer resp.Body.Close()
+
+	// Check the HTTP response status code.
+	if err := c.checkResponse(resp); err != nil {
+		return c.wrap("checking status code", err)
+	}
+
+	return nil
+}
+
+// readRuntimeGroup decodes the runtime group returned by a mutating request.
+// Minimal responses, see WithPreferMinimal, have no body: the runtime group is
+// fetched by id instead, taken from the Location header when id is empty. So
+// are non-JSON bodies, e.g. rewritten by a proxy, when the id is known.
+func (c *Client) readRuntimeGroup(ctx context.Context, resp *http.Response, id string) (*CreateRuntimeGroupResponse, error) {
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, c.wrap("reading response body", err)
+	}
+
+	if id == "" {
+		id = locationID(resp.Header.Get("Location"))
+	}
+	knownID := id != "" && id != "." && id != "/"
+
+	if len(bytes.TrimSpace(raw)) == 0 {
+		if !knownID {
+			return nil, c.wrap("reading minimal response", fmt.Errorf("no runtime group id in the Location header"))
+		}
+
+		return c.GetRuntimeGroup(ctx, id)
+	}
+
+	var group CreateRuntimeGroupResponse
+	if group.Raw, err = readJSON(bytes.NewReader(raw), &group); err != nil {
+		// Servers may omit the Content-Type of JSON bodies, only bodies that
+		// fail to decode are checked.
+		contentType := resp.Header.Get("Content-Type")
+		if isJSON(contentType) {
+			return nil, c.wrap("decoding response JSON", err)
+		}
+
+		if knownID {
+			return c.GetRuntimeGroup(ctx, id)
+		}
+
+		return nil, c.wrap("decoding response JSON", fmt.Errorf("%w: the request succeeded with a %q body, "+
+			"a proxy between the provider and the API may be rewriting responses: %s", ErrNonJSONResponse, contentType, err))
+	}
+
+	if err := c.validateResponse(&group); err != nil {
+		return nil, err
+	}
+
+	return &group, nil
+}
+
+// locationID returns the runtime group id ending the path of location, a
+// relative or absolute URL such as /runtime-groups/{id}.
+func locationID(location string) string {
+	if parsed, err := url.Parse(location); err == nil {
+		location = parsed.Path
+	}
+
+	return path.Base(location)
+}
+
+// validateResponse runs the response validators on the decoded response v.
+func (c *Client) validateResponse(v any) error {
+	for _, validate := range c.responseValidators {
+		if err := validate(v); err != nil {
+			return c.wrap("validating response", err)
+		}
+	}
+
+	return nil
+}
+
+// isJSON reports whether contentType is a JSON media type, e.g.
+// application/json or application/problem+json.
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// readJSON reads the whole body and decodes it into v, returning the raw body.
+// Decoding errors quote the start of the body, which is often an HTML error
+// page of a proxy or gateway rather than JSON.
+func readJSON(body io.Reader, v any) ([]byte, error) {
+	raw, err := io.ReadAll(body)
+	if err != nil {
+		return nil, fmt.Errorf("reading body: %w", err)
+	}
+
+	if err := json.Unmarshal(raw, v); err != nil {
+		return nil, fmt.Errorf("%w, body: %q", err, bodySnippet(raw))
+	}
+
+	return raw, nil
+}
+
+// bodySnippetLength is the number of bytes of the body quoted in errors.
+const bodySnippetLength = 256
+
+// secretPattern matches bearer tokens and JWTs that must not leak into errors.
+var secretPattern = regexp.MustCompile(`(?i)bearer\s+\S+|eyJ[\w-]+\.[\w-]+\.[\w-]*`)
+
+// bodySnippet returns the start of body with the secrets it contains redacted.
+func bodySnippet(body []byte) string {
+	return truncateBody(body, bodySnippetLength)
+}
+
+// transport returns a copy of http.DefaultTransport configured by the options.
+func (c *Client) transport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if c.forceHTTP1 {
+		// A non-nil empty TLSNextProto disables the HTTP/2 upgrade over TLS.
+		transport.ForceAttemptHTTP2 = false
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+
+	transport.DisableKeepAlives = c.disableKeepAlives
+	// The transport neither asks for gzip nor decompresses the responses.
+	transport.DisableCompression = c.disableCompression
+
+	if c.dialContext != nil {
+		transport.DialContext = c.dialContext
+	}
+
+	return transport
+}
+
+// roundTripper returns the transport wrapped by the round tripper wrappers, so
+// that the first registered wrapper sees the requests first.
+func (c *Client) roundTripper() http.RoundTripper {
+	var rt http.RoundTripper = c.transport()
+	for i := len(c.roundTripperWrappers) - 1; i >= 0; i-- {
+		rt = c.roundTripperWrappers[i](rt)
+	}
+
+	return rt
+}
+
+// endpoint joins the base URL of the operation, the base path and the given
+// endpoint elements. The base URL is the override of the operation when set.
+// Failures match ErrInvalidEndpoint.
+func (c *Client) endpoint(operation string, elem ...string) (string, error) {
+	baseURL := c.BaseUrl
+	if override, ok := c.endpointOverrides[operation]; ok {
+		baseURL = override
+	}
+
+	endpoint, err := url.JoinPath(baseURL, append([]string{c.basePath}, elem...)...)
+	if err != nil {
+		return "", &invalidEndpointError{cause: err}
+	}
+
+	return endpoint, nil
+}
+
+// ResolveURL returns the URL a request to endpoint is sent to, e.g.
+// "/runtime-groups/rg-1?filter[name][eq]=test", joined to the base URL and
+// the base path as the methods of the client do, without sending it. Endpoint
+// overrides apply per operation and are not taken into account. The endpoint
+// must be a path, with an optional query.
+func (c *Client) ResolveURL(endpoint string) (string, error) {
+	ref, err := url.Parse(endpoint)
+	if err != nil {
+		return "", c.wrap("parsing endpoint", &invalidEndpointError{cause: err})
+	}
+
+	if ref.Scheme != "" || ref.Host != "" || ref.Fragment != "" {
+		return "", c.wrap("parsing endpoint", &invalidEndpointError{cause: fmt.Errorf("%q is not a path", endpoint)})
+	}
+
+	resolved, err := c.endpoint("", ref.EscapedPath())
+	if err != nil {
+		return "", c.wrap("joining base URL and endpoint", err)
+	}
+
+	if ref.RawQuery != "" {
+		resolved += "?" + ref.RawQuery
+	}
+
+	return resolved, nil
 }
 
 // do is a wrapper for http.Client.Do
 func (c *Client) do(req *http.Request) (*http.Response, error) {
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.token))
+	start := c.clock.Now()
+
+	var resp *http.Response
+	var err error
+	if c.tracer != nil {
+		resp, err = c.doWithSpan(req)
+	} else {
+		resp, err = c.doRequest(req)
+	}
+
+	c.recordRequest(req, resp, err, start)
+
+	return resp, err
+}
+
+// doRequest sets the built-in headers and sends the request within the limits
+// set by the options.
+func (c *Client) doRequest(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.preferMinimal && mutating(req.Method) {
+		req.Header.Set("Prefer", "return=minimal")
+	}
+
+	// Gateways blocking PATCH, PUT and DELETE let POST requests through and
+	// tunnel the real method in a header.
+	if c.methodOverride && req.Method != http.MethodGet && req.Method != http.MethodPost {
+		req.Header.Set(methodOverrideHeader, req.Method)
+		req.Method = http.MethodPost
+	}
+
+	// The base context aborts the request too, including its wait for a slot.
+	cancel := func() {}
+	if c.baseCtx != nil {
+		var ctx context.Context
+		ctx, cancel = withBaseContext(req.Context(), c.baseCtx)
+		req = req.WithContext(ctx)
+	}
+
+	// The slot is released once the response headers are received: callers
+	// may send nested requests before closing the body, which must not wait
+	// for the slot they hold.
+	if c.slots != nil {
+		select {
+		case c.slots <- struct{}{}:
+			defer func() { <-c.slots }()
+		case <-req.Context().Done():
+			cancel()
+			return nil, c.wrap("waiting for a request slot", req.Context().Err())
+		}
+	}
+
+	// Requests with a deadline-less context could hang forever, bound them by
+	// the fallback timeout. A deadline set by the caller is respected, the
+	// request timeout only shortens it.
+	var timeout time.Duration
+	if _, ok := req.Context().Deadline(); !ok {
+		timeout = c.fallbackTimeout
+	}
+	if c.requestTimeout > 0 && (timeout <= 0 || c.requestTimeout < timeout) {
+		timeout = c.requestTimeout
+	}
 
-	// Perform the HTTP request.
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	if timeout <= 0 && c.baseCtx == nil {
+		return c.doWithTokenRefresh(req)
+	}
+
+	if timeout > 0 {
+		ctx, cancelTimeout := context.WithTimeout(req.Context(), timeout)
+		req = req.WithContext(ctx)
+
+		cancelBase := cancel
+		cancel = func() {
+			cancelTimeout()
+			cancelBase()
+		}
+	}
+
+	resp, err := c.doWithTokenRefresh(req)
 	if err != nil {
-		return nil, c.wrap("making HTTP request", err)
+		cancel()
+		return nil, err
+	}
+
+	// The body is read after do returns, the context lives until it is closed.
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+
+	return resp, nil
+}
+
+// baseContext is a request context also cancelled with a base context, whose
+// values it falls back to.
+type baseContext struct {
+	context.Context
+	base context.Context
+}
+
+func (c baseContext) Value(key any) any {
+	if value := c.Context.Value(key); value != nil {
+		return value
+	}
+
+	return c.base.Value(key)
+}
+
+// withBaseContext derives a context from ctx that is also done when base is,
+// with the earlier of their deadlines and the values of both, those of ctx
+// first. The returned cancel func must be called to release it.
+func withBaseContext(ctx, base context.Context) (context.Context, context.CancelFunc) {
+	var cancel context.CancelFunc
+	if deadline, ok := base.Deadline(); ok {
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+
+	go func() {
+		select {
+		case <-base.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	return baseContext{Context: ctx, base: base}, cancel
+}
+
+// mutating reports whether method changes a resource and returns it.
+func mutating(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch
+}
+
+// cancelOnClose cancels the context of a response when its body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	defer b.cancel()
+
+	return b.ReadCloser.Close()
+}
+
+// doWithTokenRefresh sends the request, retrying once with a freshly sourced
+// token when it is rejected.
+func (c *Client) doWithTokenRefresh(req *http.Request) (*http.Response, error) {
+	resp, err := c.send(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// The token may have expired while in flight: source a fresh one and retry
+	// once. A second 401 is returned as is and surfaces as ErrUnauthorized.
+	if resp.StatusCode == http.StatusUnauthorized && c.tokenSource != nil && c.rawAuthHeader == "" && canRetry(req) {
+		drainAndClose(resp)
+		c.invalidateToken()
+
+		if err := rewindBody(req); err != nil {
+			return nil, c.wrap("retrying HTTP request", err)
+		}
+
+		return c.send(req)
 	}
 
 	return resp, nil
 }
 
+// send authorizes and performs the HTTP request, retrying transient failures.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	authorization, err := c.authorization()
+	if err != nil {
+		return nil, c.wrap("getting bearer token", err)
+	}
+
+	req.Header.Set("Authorization", authorization)
+
+	for _, intercept := range c.interceptors {
+		if err := intercept(req.Context(), req); err != nil {
+			return nil, c.wrap("intercepting HTTP request", err)
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			if err := c.waitRetry(req, attempt); err != nil {
+				return nil, c.wrap("retrying HTTP request", err)
+			}
+
+			recordAttempt(req, attempt)
+		}
+
+		c.traceRequest(req)
+
+		resp, err := c.httpClient.Do(req)
+		if attempt < c.maxRetries && canRetry(req) && shouldRetry(resp, err) {
+			if resp != nil {
+				drainAndClose(resp)
+			}
+			continue
+		}
+
+		if err != nil {
+			return nil, c.wrap("making HTTP request", err)
+		}
+
+		if err := c.traceResponse(req, resp); err != nil {
+			return nil, c.wrap("reading HTTP response", err)
+		}
+
+		return resp, nil
+	}
+}
+
+// authorization returns the value of the Authorization header.
+func (c *Client) authorization() (string, error) {
+	if c.rawAuthHeader != "" {
+		return c.rawAuthHeader, nil
+	}
+
+	token, err := c.currentToken()
+	if err != nil {
+		return "", err
+	}
+
+	// Only reachable when the token validation was skipped in New.
+	if token == "" {
+		return "", fmt.Errorf("missing bearer token")
+	}
+
+	scheme := c.authScheme
+	if scheme == "" {
+		scheme = "Bearer"
+	}
+
+	return fmt.Sprintf("%s %s", scheme, token), nil
+}
+
 // wrap the client function for wrapping the error.
 func (c *Client) wrap(msg string, err error) error {
 	return fmt.Errorf("|client error: %s -> %w", msg, err)
 }
 
-func (c *Client) codeToErr(code int) error {
-	if code != http.StatusCreated && code != http.StatusOK {
-		// todo: Handle error responses (e.g., 400, 401, 403, 409, 500, 503)
-		return fmt.Errorf("HTTP request failed with status code %d", code)
+// checkResponse returns an *APIError describing unsuccessful responses.
+func (c *Client) checkResponse(resp *http.Response) error {
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return nil
 	}
-	return nil
+
+	if c.acceptAny2xx && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	return newAPIError(resp)
 }
 
-// ValidateBearerToken validates the bearer token.
-func validateBearerToken(tokenString string) error {
-	token, _, err := new(jwt.Parser).ParseUnverified(tokenString, jwt.MapClaims{})
-	if err != nil {
+// ValidateBearerToken validates the bearer token at the given time.
+func validateBearerToken(tokenString string, now time.Time) error {
+	claims := jwt.MapClaims{}
+	if _, _, err := new(jwt.Parser).ParseUnverified(tokenString, claims); err != nil {
 		return err
 	}
 
-	// Check if the token is valid.
-	if !token.Valid {
-		return fmt.Errorf("invalid token")
+	// Check if the token claims are valid (exp, nbf, iat). The signature is not
+	// verified on the client side, so token.Valid is never set by the parser.
+	// The checks mirror jwt.MapClaims.Valid against now rather than the wall
+	// clock.
+	unix := now.Unix()
+	switch {
+	case !claims.VerifyExpiresAt(unix, false):
+		return fmt.Errorf("invalid token: token is expired")
+	case !claims.VerifyIssuedAt(unix, false):
+		return fmt.Errorf("invalid token: token used before issued")
+	case !claims.VerifyNotBefore(unix, false):
+		return fmt.Errorf("invalid token: token is not valid yet")
 	}
 
 	return nil
@@ -0,0 +1,612 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// testToken returns a JWT with valid claims accepted by New.
+func testToken(t *testing.T) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "test",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("signing test token: %s", err)
+	}
+
+	return token
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New("https://example.com", testToken(t)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expired, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"exp": time.Now().Add(-time.Hour).Unix(),
+	}).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("signing test token: %s", err)
+	}
+
+	if _, err := New("https://example.com", expired); err == nil {
+		t.Fatal("expected an error for an expired token")
+	}
+
+	if _, err := New("https://example.com", "not-a-jwt"); err == nil {
+		t.Fatal("expected an error for a malformed token")
+	}
+}
+
+func TestNewSkipTokenValidation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, "", WithSkipTokenValidation(true))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Requests still require credentials.
+	if _, err := c.GetRuntimeGroup(context.Background(), "rg-1"); err == nil {
+		t.Fatal("expected an error for a missing token")
+	}
+}
+
+func TestCreateRuntimeGroupRequestValidate(t *testing.T) {
+	tests := map[string]struct {
+		request CreateRuntimeGroupRequest
+		wantErr bool
+	}{
+		"valid": {
+			request: CreateRuntimeGroupRequest{Name: "test", ClusterType: "CLUSTER_TYPE_HYBRID"},
+		},
+		"without cluster type": {
+			request: CreateRuntimeGroupRequest{Name: "test"},
+		},
+		"missing name": {
+			request: CreateRuntimeGroupRequest{ClusterType: "CLUSTER_TYPE_HYBRID"},
+			wantErr: true,
+		},
+		"bad cluster type": {
+			request: CreateRuntimeGroupRequest{Name: "test", ClusterType: "CLUSTER_TYPE_UNKNOWN"},
+			wantErr: true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := test.request.Validate()
+			if test.wantErr != (err != nil) {
+				t.Fatalf("expected error: %t, got: %v", test.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestClusterTypeValid(t *testing.T) {
+	tests := map[ClusterType]bool{
+		ClusterTypeHybrid:               true,
+		ClusterTypeK8sIngressController: true,
+		ClusterTypeComposite:            true,
+		"":                              false,
+		"CLUSTER_TYPE_UNKNOWN":          false,
+		"cluster_type_hybrid":           false,
+	}
+
+	for clusterType, want := range tests {
+		if got := clusterType.Valid(); got != want {
+			t.Errorf("expected %q to be valid: %t, got %t", clusterType, want, got)
+		}
+	}
+}
+
+func TestCreateRuntimeGroupValidatesBeforeRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := c.CreateRuntimeGroup(CreateRuntimeGroupRequest{}); err == nil {
+		t.Fatal("expected an error for a missing name")
+	}
+
+	if _, err := c.CreateRuntimeGroup(CreateRuntimeGroupRequest{Name: "test", ClusterType: "bad"}); err == nil {
+		t.Fatal("expected an error for a bad cluster type")
+	}
+}
+
+func TestGetRuntimeGroup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected method: %s", r.Method)
+		}
+
+		switch r.URL.Path {
+		case "/runtime-groups/rg-1":
+			fmt.Fprint(w, `{"id":"rg-1","name":"test","config":{"control_plane_endpoint":"https://cp.example.com"},"entity_version":3}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	group, err := c.GetRuntimeGroup(context.Background(), "rg-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if group.ID != "rg-1" || group.Config.ControlPlaneEndpoint != "https://cp.example.com" {
+		t.Fatalf("unexpected runtime group: %+v", group)
+	}
+
+	if group.EntityVersion == nil || *group.EntityVersion != 3 {
+		t.Fatalf("expected entity version 3, got %v", group.EntityVersion)
+	}
+
+	if want := `{"id":"rg-1","name":"test","config":{"control_plane_endpoint":"https://cp.example.com"},"entity_version":3}`; string(group.Raw) != want {
+		t.Fatalf("expected raw response %s, got %s", want, group.Raw)
+	}
+
+	if _, err := c.GetRuntimeGroup(context.Background(), "missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got: %v", err)
+	}
+}
+
+func TestRuntimeGroupRequestLabelsPayload(t *testing.T) {
+	tests := map[string]struct {
+		labels map[string]string
+		want   string
+	}{
+		"omitted": {
+			labels: nil,
+			want:   `{"name":"test","description":""}`,
+		},
+		"empty": {
+			labels: map[string]string{},
+			want:   `{"name":"test","description":"","labels":{}}`,
+		},
+		"populated": {
+			labels: map[string]string{"env": "dev"},
+			want:   `{"name":"test","description":"","labels":{"env":"dev"}}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := json.Marshal(UpdateRuntimeGroupRequest{Name: "test", Labels: test.labels})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if string(got) != test.want {
+				t.Fatalf("expected %s, got %s", test.want, got)
+			}
+
+			got, err = json.Marshal(CreateRuntimeGroupRequest{Name: "test", ClusterType: "CLUSTER_TYPE_HYBRID", Labels: test.labels})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			want := strings.Replace(test.want, `"description":""`, `"description":"","cluster_type":"CLUSTER_TYPE_HYBRID"`, 1)
+			if string(got) != want {
+				t.Fatalf("expected %s, got %s", want, got)
+			}
+		})
+	}
+}
+
+func TestUpdateRuntimeGroup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/runtime-groups/rg-1" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("reading request body: %s", err)
+		}
+
+		if want := `{"name":"renamed","description":"","labels":{}}`; string(body) != want {
+			t.Errorf("expected body %s, got %s", want, body)
+		}
+
+		fmt.Fprint(w, `{"id":"rg-1","name":"renamed"}`)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	group, err := c.UpdateRuntimeGroup(context.Background(), "rg-1", UpdateRuntimeGroupRequest{Name: "renamed", Labels: map[string]string{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if group.Name != "renamed" {
+		t.Fatalf("unexpected runtime group: %+v", group)
+	}
+}
+
+func TestCreateRuntimeGroupResponseDecode(t *testing.T) {
+	body := `{
+		"id": "rg-1",
+		"name": "test",
+		"description": "a runtime group",
+		"labels": {"env": "test"},
+		"config": {
+			"control_plane_endpoint": "https://cp.example.com",
+			"telemetry_endpoint": "https://tp.example.com"
+		},
+		"created_at": "2023-01-01T00:00:00Z",
+		"updated_at": "2023-01-02T00:00:00Z"
+	}`
+
+	var got CreateRuntimeGroupResponse
+	if err := json.Unmarshal([]byte(body), &got); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := CreateRuntimeGroupResponse{
+		ID:          "rg-1",
+		Name:        "test",
+		Description: "a runtime group",
+		Labels:      map[string]string{"env": "test"},
+		Config: RuntimeGroupConfig{
+			ControlPlaneEndpoint: "https://cp.example.com",
+			TelemetryEndpoint:    "https://tp.example.com",
+		},
+		CreatedAt: "2023-01-01T00:00:00Z",
+		UpdatedAt: "2023-01-02T00:00:00Z",
+		Raw:       json.RawMessage(body),
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestCreateRuntimeGroupDecodeErrorSnippet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, "<html><body><h1>502 Bad Gateway</h1><p>Authorization: Bearer secret-token</p>"+strings.Repeat("padding ", 100)+"</body></html>")
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	_, err = c.CreateRuntimeGroup(CreateRuntimeGroupRequest{Name: "test"})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	msg := err.Error()
+	if !strings.Contains(msg, "<h1>502 Bad Gateway</h1>") {
+		t.Fatalf("expected the body snippet in the error, got: %s", msg)
+	}
+
+	if strings.Contains(msg, "secret-token") || !strings.Contains(msg, "[REDACTED]") {
+		t.Fatalf("expected the token to be redacted, got: %s", msg)
+	}
+
+	if strings.Contains(msg, "</html>") || !strings.Contains(msg, `..."`) {
+		t.Fatalf("expected the snippet to be truncated, got: %s", msg)
+	}
+}
+
+func TestDeleteRuntimeGroup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/runtime-groups/rg-1" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := c.DeleteRuntimeGroup(context.Background(), "rg-1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestWithPreferMinimal(t *testing.T) {
+	tests := map[string]struct {
+		preferMinimal bool
+		wantGets      int
+	}{
+		"minimal":        {preferMinimal: true, wantGets: 2},
+		"representation": {preferMinimal: false, wantGets: 0},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var gets int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				minimal := r.Header.Get("Prefer") == "return=minimal"
+
+				switch r.Method {
+				case http.MethodGet:
+					gets++
+					if minimal {
+						t.Errorf("unexpected Prefer header on %s", r.Method)
+					}
+				case http.MethodPost:
+					if minimal {
+						w.Header().Set("Location", "/runtime-groups/rg-1")
+						w.WriteHeader(http.StatusCreated)
+						return
+					}
+					w.WriteHeader(http.StatusCreated)
+				case http.MethodPatch:
+					if minimal {
+						w.WriteHeader(http.StatusNoContent)
+						return
+					}
+				}
+
+				fmt.Fprint(w, `{"id":"rg-1","name":"test","config":{"control_plane_endpoint":"https://cp.example.com"}}`)
+			}))
+			defer server.Close()
+
+			c, err := New(server.URL, testToken(t), WithPreferMinimal(tt.preferMinimal))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			created, err := c.CreateRuntimeGroup(CreateRuntimeGroupRequest{Name: "test"})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			updated, err := c.UpdateRuntimeGroup(context.Background(), "rg-1", UpdateRuntimeGroupRequest{Name: "test"})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			for _, group := range []*CreateRuntimeGroupResponse{created, updated} {
+				if group.ID != "rg-1" || group.Config.ControlPlaneEndpoint != "https://cp.example.com" {
+					t.Fatalf("expected the computed fields to be populated, got %+v", group)
+				}
+			}
+
+			if gets != tt.wantGets {
+				t.Fatalf("expected %d GET requests, got %d", tt.wantGets, gets)
+			}
+		})
+	}
+}
+
+func TestWithPreferMinimalMissingLocation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t), WithPreferMinimal(true))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := c.CreateRuntimeGroup(CreateRuntimeGroupRequest{Name: "test"}); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestCreateRuntimeGroupLocationOnly(t *testing.T) {
+	tests := map[string]string{
+		"relative":   "/runtime-groups/rg-1",
+		"absolute":   "https://example.com/v2/runtime-groups/rg-1",
+		"with query": "/runtime-groups/rg-1?include=status",
+	}
+
+	for name, location := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost {
+					w.Header().Set("Location", location)
+					w.WriteHeader(http.StatusCreated)
+					return
+				}
+
+				if r.URL.Path != "/runtime-groups/rg-1" {
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+				}
+				fmt.Fprint(w, `{"id":"rg-1","name":"test","config":{"control_plane_endpoint":"https://cp.example.com"}}`)
+			}))
+			defer server.Close()
+
+			c, err := New(server.URL, testToken(t))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			group, err := c.CreateRuntimeGroup(CreateRuntimeGroupRequest{Name: "test"})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if group.ID != "rg-1" || group.Config.ControlPlaneEndpoint != "https://cp.example.com" {
+				t.Fatalf("expected the runtime group to be fetched from the Location header, got %+v", group)
+			}
+		})
+	}
+}
+
+func TestCreateRuntimeGroupFixture(t *testing.T) {
+	server := fixtureServer(t, "create_runtime_group.yaml")
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	group, err := c.CreateRuntimeGroup(CreateRuntimeGroupRequest{Name: "fixture", Labels: map[string]string{"env": "test"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if group.ID != "7f9fd312-a987-4628-b4c5-bb4f4fddd5f7" || group.Labels["env"] != "test" || group.Config.TelemetryEndpoint != "https://tp.example.com" {
+		t.Fatalf("unexpected runtime group: %+v", group)
+	}
+}
+
+func TestGetRuntimeGroupFixtureNotFound(t *testing.T) {
+	server := fixtureServer(t, "get_runtime_group_not_found.json")
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := c.GetRuntimeGroup(context.Background(), "missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestCreateRuntimeGroupNonJSONResponse(t *testing.T) {
+	tests := map[string]struct {
+		location string
+		wantErr  error
+	}{
+		"without location": {wantErr: ErrNonJSONResponse},
+		"with location":    {location: "/runtime-groups/rg-1"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodGet && r.URL.Path == "/runtime-groups/rg-1" {
+					w.Header().Set("Content-Type", "application/json")
+					fmt.Fprint(w, `{"id":"rg-1","name":"test"}`)
+					return
+				}
+
+				// A proxy replaced the JSON body of the successful response.
+				if tt.location != "" {
+					w.Header().Set("Location", tt.location)
+				}
+				w.Header().Set("Content-Type", "text/plain")
+				w.WriteHeader(http.StatusCreated)
+				fmt.Fprint(w, "OK")
+			}))
+			defer server.Close()
+
+			c, err := New(server.URL, testToken(t))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			group, err := c.CreateRuntimeGroup(CreateRuntimeGroupRequest{Name: "test"})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || !strings.Contains(err.Error(), "proxy") {
+					t.Fatalf("expected %v advising a proxy issue, got %v", tt.wantErr, err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if group.ID != "rg-1" {
+				t.Fatalf("expected the runtime group to be re-fetched, got %+v", group)
+			}
+		})
+	}
+}
+
+func TestUpdateRuntimeGroupMergePatch(t *testing.T) {
+	tests := map[string]struct {
+		request UpdateRuntimeGroupRequest
+		want    string
+	}{
+		"changed and removed labels": {
+			request: UpdateRuntimeGroupRequest{
+				Name:        "test",
+				Labels:      map[string]string{"env": "prod", "team": "core", "tier": "1"},
+				PriorLabels: map[string]string{"env": "dev", "team": "core", "owner": "alice"},
+			},
+			want: `{"name":"test","description":"","labels":{"env":"prod","owner":null,"tier":"1"}}`,
+		},
+		"unchanged labels": {
+			request: UpdateRuntimeGroupRequest{
+				Name:        "test",
+				Labels:      map[string]string{"env": "dev"},
+				PriorLabels: map[string]string{"env": "dev"},
+			},
+			want: `{"name":"test","description":""}`,
+		},
+		"nil labels": {
+			request: UpdateRuntimeGroupRequest{Name: "test", PriorLabels: map[string]string{"env": "dev"}},
+			want:    `{"name":"test","description":""}`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("Content-Type"); got != "application/merge-patch+json" {
+					t.Errorf("expected a merge patch content type, got %q", got)
+				}
+
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Errorf("reading request body: %s", err)
+				}
+
+				if string(body) != tt.want {
+					t.Errorf("expected body %s, got %s", tt.want, body)
+				}
+
+				fmt.Fprint(w, `{"id":"rg-1","name":"test"}`)
+			}))
+			defer server.Close()
+
+			c, err := New(server.URL, testToken(t), WithMergePatch(true))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if _, err := c.UpdateRuntimeGroup(context.Background(), "rg-1", tt.request); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrUnknownAccount is returned by ClientSet.Get for an account it has no
+// client for.
+var ErrUnknownAccount = errors.New("unknown account")
+
+// AccountConfig configures the client of one Konnect account of a ClientSet.
+type AccountConfig struct {
+	// Name identifies the account in the ClientSet.
+	Name    string
+	BaseURL string
+	Token   string
+	// Options are applied after the options shared by every account.
+	Options []Option
+}
+
+// ClientSet holds a client per Konnect account, for tools fanning out over
+// several accounts. It is safe for concurrent use.
+type ClientSet struct {
+	clients map[string]*Client
+}
+
+// NewClientSet is a constructor for ClientSet building a client per account
+// as by New, with opts applied to every client before the options of the
+// account. Account names must be unique and not empty.
+func NewClientSet(accounts []AccountConfig, opts ...Option) (*ClientSet, error) {
+	clients := make(map[string]*Client, len(accounts))
+	for _, account := range accounts {
+		if account.Name == "" {
+			return nil, fmt.Errorf("account name must not be empty")
+		}
+		if _, ok := clients[account.Name]; ok {
+			return nil, fmt.Errorf("duplicate account %q", account.Name)
+		}
+
+		accountOpts := append(append([]Option{}, opts...), account.Options...)
+		c, err := New(account.BaseURL, account.Token, accountOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("creating client of account %q: %w", account.Name, err)
+		}
+
+		clients[account.Name] = c
+	}
+
+	return &ClientSet{clients: clients}, nil
+}
+
+// Get returns the client of account, or an error matching ErrUnknownAccount
+// when the set has none.
+func (s *ClientSet) Get(account string) (*Client, error) {
+	c, ok := s.clients[account]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownAccount, account)
+	}
+
+	return c, nil
+}
+
+// Accounts returns the names of the accounts of the set, sorted.
+func (s *ClientSet) Accounts() []string {
+	accounts := make([]string, 0, len(s.clients))
+	for account := range s.clients {
+		accounts = append(accounts, account)
+	}
+	sort.Strings(accounts)
+
+	return accounts
+}
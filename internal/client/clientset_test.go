@@ -0,0 +1,74 @@
+package client
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClientSet(t *testing.T) {
+	set, err := NewClientSet([]AccountConfig{
+		{Name: "prod", BaseURL: "https://prod.example.com", Token: testToken(t)},
+		{Name: "dev", BaseURL: "https://dev.example.com", Token: testToken(t), Options: []Option{WithBasePath("/v2")}},
+	}, WithDefaultLabels(map[string]string{"managed-by": "fanout"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if want := []string{"dev", "prod"}; !reflect.DeepEqual(set.Accounts(), want) {
+		t.Fatalf("expected accounts %v, got %v", want, set.Accounts())
+	}
+
+	tests := map[string]struct {
+		account     string
+		wantBaseURL string
+		wantErr     error
+	}{
+		"prod":    {account: "prod", wantBaseURL: "https://prod.example.com"},
+		"dev":     {account: "dev", wantBaseURL: "https://dev.example.com"},
+		"missing": {account: "staging", wantErr: ErrUnknownAccount},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := set.Get(tt.account)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || !strings.Contains(err.Error(), tt.account) {
+					t.Fatalf("expected %v naming %q, got %v", tt.wantErr, tt.account, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if c.BaseUrl != tt.wantBaseURL {
+				t.Fatalf("expected base URL %s, got %s", tt.wantBaseURL, c.BaseUrl)
+			}
+
+			if c.DefaultLabels()["managed-by"] != "fanout" {
+				t.Fatalf("expected the shared options applied, got labels %v", c.DefaultLabels())
+			}
+		})
+	}
+}
+
+func TestNewClientSetInvalid(t *testing.T) {
+	tests := map[string][]AccountConfig{
+		"empty name": {{BaseURL: "https://example.com", Token: testToken(t)}},
+		"duplicate": {
+			{Name: "prod", BaseURL: "https://example.com", Token: testToken(t)},
+			{Name: "prod", BaseURL: "https://example.com", Token: testToken(t)},
+		},
+		"invalid token": {{Name: "prod", BaseURL: "https://example.com", Token: "invalid"}},
+	}
+
+	for name, accounts := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewClientSet(accounts); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package client
+
+import (
+	"context"
+	"time"
+)
+
+// clock abstracts the time-dependent code paths of the Client, i.e. the retry
+// backoff and the token expiry checks, so they can be tested without sleeping.
+type clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// Sleep waits for d, returning early with the context error when ctx is done.
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+// realClock is the default clock, backed by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// fakeClock is a clock whose Sleep records the delay and advances Now
+// instead of waiting.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.now
+}
+
+func (f *fakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.sleeps = append(f.sleeps, d)
+	f.now = f.now.Add(d)
+
+	return ctx.Err()
+}
+
+func TestWithClockBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	clk := &fakeClock{now: time.Now()}
+	c, err := New(server.URL, testToken(t), WithRetry(3, time.Second), WithBackoff(ExponentialBackoff{}), WithClock(clk))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := c.GetRuntimeGroup(context.Background(), "rg-1"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}
+	if !reflect.DeepEqual(clk.sleeps, want) {
+		t.Fatalf("expected backoff %v, got %v", want, clk.sleeps)
+	}
+}
+
+func TestWithClockTokenExpiry(t *testing.T) {
+	expiry := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "test",
+		"exp": expiry.Unix(),
+	}).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("signing test token: %s", err)
+	}
+
+	tests := map[string]struct {
+		now     time.Time
+		wantErr bool
+	}{
+		"before expiry": {now: expiry.Add(-time.Minute)},
+		"after expiry":  {now: expiry.Add(time.Minute), wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := New("https://example.com", token, WithClock(&fakeClock{now: tt.now}))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %t, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestWithClockNil(t *testing.T) {
+	if _, err := New("https://example.com", testToken(t), WithClock(nil)); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
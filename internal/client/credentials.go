@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+const (
+	// runtimeGroupRotateCredentialsEndpoint is the action of a runtime group rotating its data plane credentials.
+	runtimeGroupRotateCredentialsEndpoint = "rotate-credentials"
+
+	// rotateRuntimeGroupCredentialsMethod is the HTTP method for rotating the credentials of a runtime group.
+	rotateRuntimeGroupCredentialsMethod = http.MethodPost
+)
+
+// RotateRuntimeGroupCredentials sends a POST request to rotate the data plane
+// certificates and endpoints of the runtime group with the given id, returning
+// the runtime group with its new endpoints. ErrNotFound or ErrNotSupported is
+// returned when the API does not expose the action.
+func (c *Client) RotateRuntimeGroupCredentials(ctx context.Context, id string) (*CreateRuntimeGroupResponse, error) {
+	defer c.locks.lock(id)()
+
+	c.cache.invalidate(id)
+	defer c.cache.invalidate(id)
+
+	endpoint, err := c.endpoint(OperationRotateRuntimeGroupCredentials, runtimeGroupEndpoint, url.PathEscape(id), runtimeGroupRotateCredentialsEndpoint)
+	if err != nil {
+		return nil, c.wrap("joining base URL and endpoint", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, rotateRuntimeGroupCredentialsMethod, endpoint, nil)
+	if err != nil {
+		return nil, c.wrap("creating HTTP request", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, c.wrap("making HTTP request", err)
+	}
+	defer resp.Body.Close()
+
+	// Check the HTTP response status code.
+	if err := c.checkResponse(resp); err != nil {
+		return nil, c.wrap("checking status code", err)
+	}
+
+	return c.readRuntimeGroup(ctx, resp, id)
+}
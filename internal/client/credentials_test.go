@@ -0,0 +1,51 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRotateRuntimeGroupCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/runtime-groups/rg-1/rotate-credentials" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+
+		fmt.Fprint(w, `{"id":"rg-1","config":{"control_plane_endpoint":"https://cp2.example.com","telemetry_endpoint":"https://tp2.example.com"}}`)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	group, err := c.RotateRuntimeGroupCredentials(context.Background(), "rg-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if group.Config.ControlPlaneEndpoint != "https://cp2.example.com" || group.Config.TelemetryEndpoint != "https://tp2.example.com" {
+		t.Fatalf("unexpected endpoints: %+v", group.Config)
+	}
+}
+
+func TestRotateRuntimeGroupCredentialsNotSupported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotImplemented)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := c.RotateRuntimeGroupCredentials(context.Background(), "rg-1"); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("expected ErrNotSupported, got %v", err)
+	}
+}
@@ -0,0 +1,44 @@
+package client
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Environment variables read by FromEnv.
+const (
+	BaseURLEnvVar = "KONNECT_BASE_URL"
+	TokenEnvVar   = "KONNECT_TOKEN"
+	RegionEnvVar  = "KONNECT_REGION"
+)
+
+// regionalBaseURL is the format of the Konnect API base URL of a region.
+const regionalBaseURL = "https://%s.api.konghq.com/v2"
+
+// FromEnv is a constructor for Client reading its configuration from the
+// environment, e.g. for scripts and tests: the base URL from KONNECT_BASE_URL,
+// or from the region in KONNECT_REGION when it is not set, and the token from
+// KONNECT_TOKEN. The error lists every missing variable. The options are
+// applied as by New.
+func FromEnv(opts ...Option) (*Client, error) {
+	baseURL := os.Getenv(BaseURLEnvVar)
+	if region := os.Getenv(RegionEnvVar); baseURL == "" && region != "" {
+		baseURL = fmt.Sprintf(regionalBaseURL, region)
+	}
+	token := os.Getenv(TokenEnvVar)
+
+	var missing []string
+	if baseURL == "" {
+		missing = append(missing, BaseURLEnvVar+" (or "+RegionEnvVar+")")
+	}
+	if token == "" {
+		missing = append(missing, TokenEnvVar)
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing environment variables: %s", strings.Join(missing, ", "))
+	}
+
+	return New(baseURL, token, opts...)
+}
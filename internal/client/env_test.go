@@ -0,0 +1,68 @@
+package client
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFromEnv(t *testing.T) {
+	token := testToken(t)
+
+	tests := map[string]struct {
+		env         map[string]string
+		wantBaseURL string
+		// wantMissing are the variables expected in the error, none for success.
+		wantMissing []string
+	}{
+		"all present": {
+			env:         map[string]string{BaseURLEnvVar: "https://example.com/v2", TokenEnvVar: token, RegionEnvVar: "eu"},
+			wantBaseURL: "https://example.com/v2",
+		},
+		"region": {
+			env:         map[string]string{TokenEnvVar: token, RegionEnvVar: "eu"},
+			wantBaseURL: "https://eu.api.konghq.com/v2",
+		},
+		"missing token": {
+			env:         map[string]string{BaseURLEnvVar: "https://example.com/v2"},
+			wantMissing: []string{TokenEnvVar},
+		},
+		"missing base URL": {
+			env:         map[string]string{TokenEnvVar: token},
+			wantMissing: []string{BaseURLEnvVar},
+		},
+		"missing all": {
+			wantMissing: []string{BaseURLEnvVar, TokenEnvVar},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			for _, key := range []string{BaseURLEnvVar, TokenEnvVar, RegionEnvVar} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			c, err := FromEnv()
+			if len(tt.wantMissing) > 0 {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+
+				for _, key := range tt.wantMissing {
+					if !strings.Contains(err.Error(), key) {
+						t.Fatalf("expected %s in error, got %s", key, err)
+					}
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if c.BaseUrl != tt.wantBaseURL {
+				t.Fatalf("expected base URL %q, got %q", tt.wantBaseURL, c.BaseUrl)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+var (
+	// ErrNotFound is returned when the requested runtime group does not exist.
+	ErrNotFound = errors.New("runtime group not found")
+	// ErrConflict is returned when a runtime group with the same name already exists.
+	ErrConflict = errors.New("runtime group already exists")
+	// ErrNotSupported is returned when the API does not implement an operation.
+	ErrNotSupported = errors.New("operation not supported by the API")
+	// ErrUnauthorized is returned when the API rejects the bearer token, even
+	// after sourcing a fresh one from the TokenSource.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrInvalidEndpoint is returned when the URL of a request cannot be built
+	// from the base URL, e.g. after BaseUrl was set to a malformed URL.
+	ErrInvalidEndpoint = errors.New("invalid endpoint")
+	// ErrNonJSONResponse is returned when a successful response carries a body
+	// that is not JSON, often rewritten or stripped by a proxy.
+	ErrNonJSONResponse = errors.New("non-JSON response")
+)
+
+// requestIDHeaders are the response headers carrying the id the API assigned
+// to the request, in order of preference.
+var requestIDHeaders = []string{"X-Kong-Request-Id", "X-Request-Id"}
+
+// APIError represents an unsuccessful response of the API. The body is
+// decoded from the problem+json error object when the API returns one.
+type APIError struct {
+	StatusCode int `json:"status"`
+	// Status is the standard text of the status code, e.g. "Conflict", empty
+	// for unknown codes.
+	Status   string `json:"-"`
+	Title    string `json:"title"`
+	Type     string `json:"type"`
+	Instance string `json:"instance"`
+	Detail   string `json:"detail"`
+	// RequestID identifies the request to Konnect support, empty when the
+	// response has no request id header.
+	RequestID string `json:"-"`
+}
+
+// newAPIError builds an APIError from an unsuccessful response.
+func newAPIError(resp *http.Response) *APIError {
+	var apiErr APIError
+
+	// The body is informative only, a missing or non-JSON body is not an error.
+	_ = json.NewDecoder(resp.Body).Decode(&apiErr)
+	apiErr.StatusCode = resp.StatusCode
+	apiErr.Status = http.StatusText(resp.StatusCode)
+
+	for _, header := range requestIDHeaders {
+		if id := resp.Header.Get(header); id != "" {
+			apiErr.RequestID = id
+			break
+		}
+	}
+
+	return &apiErr
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("HTTP request failed with status code %d", e.StatusCode)
+
+	if e.Status != "" {
+		msg += " " + e.Status
+	}
+
+	// Problem titles often repeat the status text.
+	if e.Title != "" && e.Title != e.Status {
+		msg += ": " + e.Title
+	}
+
+	if e.Detail != "" {
+		msg += " (" + e.Detail + ")"
+	}
+
+	return msg
+}
+
+// Unwrap maps the status code to the matching sentinel error, so callers can
+// use errors.Is(err, ErrNotFound) and the like.
+func (e *APIError) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return ErrNotSupported
+	}
+
+	return nil
+}
+
+// invalidEndpointError wraps the cause of a failure to build the URL of a
+// request, matching ErrInvalidEndpoint with errors.Is.
+type invalidEndpointError struct {
+	cause error
+}
+
+func (e *invalidEndpointError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrInvalidEndpoint, e.cause)
+}
+
+func (e *invalidEndpointError) Unwrap() error {
+	return e.cause
+}
+
+func (e *invalidEndpointError) Is(target error) bool {
+	return target == ErrInvalidEndpoint
+}
+
+// IsTimeout reports whether err, or any error it wraps, is a network timeout.
+// The client wraps errors with %w, so the original net.Error stays reachable.
+func IsTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,184 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestIsTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Deliberately slow: answer only once the client gave up.
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err = c.GetRuntimeGroup(ctx, "rg-1")
+	if !IsTimeout(err) {
+		t.Fatalf("expected a timeout error, got: %v", err)
+	}
+
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Fatalf("expected the error to unwrap to a timing out net.Error, got: %v", err)
+	}
+}
+
+func TestIsTimeoutOtherErrors(t *testing.T) {
+	if IsTimeout(nil) {
+		t.Fatal("expected nil not to be a timeout")
+	}
+
+	if IsTimeout(ErrNotFound) {
+		t.Fatal("expected ErrNotFound not to be a timeout")
+	}
+}
+
+func TestCreateRuntimeGroupConflict(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"status":409,"title":"Conflict","instance":"konnect:trace:1","detail":"Key (org_id, name) already exists."}`)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	_, err = c.CreateRuntimeGroup(CreateRuntimeGroupRequest{Name: "test"})
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict, got: %v", err)
+	}
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an *APIError, got: %T", err)
+	}
+
+	if apiErr.StatusCode != http.StatusConflict || apiErr.Instance != "konnect:trace:1" || apiErr.Detail == "" {
+		t.Fatalf("unexpected API error: %+v", apiErr)
+	}
+}
+
+func TestAPIErrorWithoutBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	_, err = c.GetRuntimeGroup(context.Background(), "rg-1")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected a 503 *APIError, got: %v", err)
+	}
+}
+
+func TestAPIErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	_, err = c.GetRuntimeGroup(context.Background(), "rg-1")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Status != "Conflict" {
+		t.Fatalf("expected an *APIError with status Conflict, got: %v", err)
+	}
+
+	if want := "HTTP request failed with status code 409 Conflict"; apiErr.Error() != want {
+		t.Fatalf("expected %q, got %q", want, apiErr.Error())
+	}
+}
+
+func TestAPIErrorRequestID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Kong-Request-Id", "req-1")
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	_, err = c.GetRuntimeGroup(context.Background(), "rg-1")
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.RequestID != "req-1" {
+		t.Fatalf("expected an *APIError with request id req-1, got: %v", err)
+	}
+}
+
+func TestInvalidEndpoint(t *testing.T) {
+	c, err := New("https://example.com", testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// BaseUrl is exported, nothing prevents callers from breaking it after New.
+	c.BaseUrl = "https://[::1"
+
+	calls := map[string]func() error{
+		"create": func() error {
+			_, err := c.CreateRuntimeGroup(CreateRuntimeGroupRequest{Name: "test"})
+			return err
+		},
+		"get": func() error {
+			_, err := c.GetRuntimeGroup(context.Background(), "rg-1")
+			return err
+		},
+		"update": func() error {
+			_, err := c.UpdateRuntimeGroup(context.Background(), "rg-1", UpdateRuntimeGroupRequest{Name: "test"})
+			return err
+		},
+		"delete": func() error {
+			return c.DeleteRuntimeGroup(context.Background(), "rg-1")
+		},
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			err := call()
+			if !errors.Is(err, ErrInvalidEndpoint) {
+				t.Fatalf("expected ErrInvalidEndpoint, got %v", err)
+			}
+
+			var urlErr *url.Error
+			if !errors.As(err, &urlErr) {
+				t.Fatalf("expected the cause to be wrapped, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package client
+
+import "sync"
+
+// etagCache holds the runtime groups read by id with their ETag, see
+// WithETagCache. A nil etagCache is a disabled cache.
+//
+// Entries are never invalidated: the API compares the ETag with the current
+// version of the runtime group and returns it in full when it changed.
+type etagCache struct {
+	mu      sync.Mutex
+	entries map[string]etagEntry
+}
+
+// etagEntry is a cached runtime group, read with the given query.
+type etagEntry struct {
+	query string
+	etag  string
+	group *CreateRuntimeGroupResponse
+}
+
+// get returns the cached entry of the runtime group with the given id, read
+// with the same query.
+func (ec *etagCache) get(id, query string) (etagEntry, bool) {
+	if ec == nil {
+		return etagEntry{}, false
+	}
+
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+
+	entry, ok := ec.entries[id]
+	if !ok || entry.query != query {
+		return etagEntry{}, false
+	}
+
+	return etagEntry{query: entry.query, etag: entry.etag, group: copyRuntimeGroup(entry.group)}, true
+}
+
+// put caches a copy of group with its ETag, unless it has none.
+func (ec *etagCache) put(query, etag string, group *CreateRuntimeGroupResponse) {
+	if ec == nil || etag == "" {
+		return
+	}
+
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+
+	if ec.entries == nil {
+		ec.entries = make(map[string]etagEntry)
+	}
+	ec.entries[group.ID] = etagEntry{query: query, etag: etag, group: copyRuntimeGroup(group)}
+}
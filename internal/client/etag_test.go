@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestETagCache(t *testing.T) {
+	var conditions []string
+	name := "test"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conditions = append(conditions, r.Header.Get("If-None-Match"))
+
+		etag := `"` + name + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", etag)
+		fmt.Fprintf(w, `{"id":"rg-1","name":%q,"labels":{"env":"dev"}}`, name)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t), WithETagCache(true))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ctx := context.Background()
+	read := func(wantName string) {
+		t.Helper()
+
+		group, err := c.GetRuntimeGroup(ctx, "rg-1")
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if group.Name != wantName || group.Labels["env"] != "dev" {
+			t.Fatalf("expected runtime group %s, got %+v", wantName, group)
+		}
+	}
+
+	read("test")
+	// Not modified: the cached runtime group is returned.
+	read("test")
+	// Modified: the new runtime group replaces the cached one.
+	name = "renamed"
+	read("renamed")
+
+	if want := []string{"", `"test"`, `"test"`}; !reflect.DeepEqual(conditions, want) {
+		t.Fatalf("expected If-None-Match headers %q, got %q", want, conditions)
+	}
+}
+
+func TestETagCacheDisabledByDefault(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			t.Errorf("unexpected If-None-Match header %q", r.Header.Get("If-None-Match"))
+		}
+
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `{"id":"rg-1"}`)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.GetRuntimeGroup(context.Background(), "rg-1"); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fixture is a canned response served by fixtureServer. Fixture files hold a
+// list of fixtures in YAML, or in JSON which YAML parses as well.
+type fixture struct {
+	// Method and Path select the requests answered by the fixture.
+	Method string `yaml:"method"`
+	Path   string `yaml:"path"`
+
+	// Status defaults to 200 OK.
+	Status  int               `yaml:"status"`
+	Headers map[string]string `yaml:"headers"`
+	Body    string            `yaml:"body"`
+}
+
+// fixtureServer returns a server answering requests with the fixtures of the
+// testdata file name, keyed by method and path. Requests without a fixture
+// fail the test and are answered with 404 Not Found.
+func fixtureServer(t *testing.T, name string) *httptest.Server {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("reading fixture file: %s", err)
+	}
+
+	var fixtures []fixture
+	if err := yaml.Unmarshal(data, &fixtures); err != nil {
+		t.Fatalf("parsing fixture file %s: %s", name, err)
+	}
+
+	routes := make(map[string]fixture, len(fixtures))
+	for _, f := range fixtures {
+		routes[f.Method+" "+f.Path] = f
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, ok := routes[r.Method+" "+r.URL.Path]
+		if !ok {
+			t.Errorf("no fixture for %s %s in %s", r.Method, r.URL.Path, name)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		for k, v := range f.Headers {
+			w.Header().Set(k, v)
+		}
+
+		status := f.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		w.WriteHeader(status)
+
+		_, _ = w.Write([]byte(f.Body))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"strconv"
+)
+
+// RuntimeGroupIterator streams the runtime groups of ListRuntimeGroups, one
+// page in memory at a time. Runtime groups are returned in API order.
+//
+//	it := c.IterateRuntimeGroups(client.ListOptions{PageSize: 100})
+//	for it.Next(ctx) {
+//		group := it.Value()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type RuntimeGroupIterator struct {
+	client *Client
+	opts   ListOptions
+
+	// page is the current page, index the position of the next runtime group.
+	page  []CreateRuntimeGroupResponse
+	index int
+	// fetched counts the runtime groups of the pages fetched so far, returned
+	// those returned by Next.
+	fetched  int
+	returned int
+	// last is set once the last page is fetched.
+	last bool
+
+	value CreateRuntimeGroupResponse
+	err   error
+}
+
+// IterateRuntimeGroups returns an iterator over the runtime groups selected by
+// opts, starting from the page in opts and stopping after opts.Limit runtime
+// groups. No request is sent until Next is called.
+func (c *Client) IterateRuntimeGroups(opts ListOptions) *RuntimeGroupIterator {
+	if opts.PageNumber < 1 {
+		opts.PageNumber = 1
+	}
+
+	return &RuntimeGroupIterator{client: c, opts: opts}
+}
+
+// Next advances to the next runtime group, fetching the next page when the
+// current one is exhausted. It returns false at the end of the runtime groups
+// or on error, see Err.
+func (it *RuntimeGroupIterator) Next(ctx context.Context) bool {
+	if it.err != nil || (it.opts.Limit > 0 && it.returned >= it.opts.Limit) {
+		return false
+	}
+
+	for it.index >= len(it.page) {
+		if it.last {
+			return false
+		}
+
+		if err := it.fetch(ctx); err != nil {
+			it.err = err
+			return false
+		}
+	}
+
+	it.value = it.page[it.index]
+	it.index++
+	it.returned++
+
+	return true
+}
+
+// fetch replaces the current page with the next one.
+func (it *RuntimeGroupIterator) fetch(ctx context.Context) error {
+	page, err := it.client.ListRuntimeGroups(ctx, it.opts)
+	if err != nil {
+		return it.client.wrap("listing page "+strconv.Itoa(it.opts.PageNumber), err)
+	}
+
+	it.page = page.Data
+	it.index = 0
+	it.fetched += len(page.Data)
+	it.last = lastPage(page, it.opts.PageSize, it.fetched)
+	it.opts.PageNumber++
+
+	return nil
+}
+
+// Value returns the runtime group Next advanced to.
+func (it *RuntimeGroupIterator) Value() CreateRuntimeGroupResponse {
+	return it.value
+}
+
+// Err returns the error that stopped the iteration, nil when the runtime
+// groups were exhausted.
+func (it *RuntimeGroupIterator) Err() error {
+	return it.err
+}
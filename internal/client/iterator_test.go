@@ -0,0 +1,113 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// pagedServer serves the given pages of runtime groups by page number, and a
+// 500 Internal Server Error for the pages in failing.
+func pagedServer(t *testing.T, pages map[string]string, failing ...string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		number := r.URL.Query().Get("page[number]")
+		for _, failed := range failing {
+			if number == failed {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
+
+		page, ok := pages[number]
+		if !ok {
+			t.Errorf("unexpected page %q", number)
+		}
+
+		fmt.Fprint(w, page)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestRuntimeGroupIterator(t *testing.T) {
+	pages := map[string]string{
+		"1": `{"meta":{"page":{"number":1,"size":2,"total":5}},"data":[{"id":"a"},{"id":"b"}]}`,
+		"2": `{"meta":{"page":{"number":2,"size":2,"total":5}},"data":[{"id":"c"},{"id":"d"}]}`,
+		"3": `{"meta":{"page":{"number":3,"size":2,"total":5}},"data":[{"id":"e"}]}`,
+	}
+
+	tests := map[string]struct {
+		opts    ListOptions
+		failing []string
+		wantIDs []string
+		wantErr bool
+	}{
+		"all pages": {
+			opts:    ListOptions{PageSize: 2},
+			wantIDs: []string{"a", "b", "c", "d", "e"},
+		},
+		"limit": {
+			opts:    ListOptions{PageSize: 2, Limit: 3},
+			wantIDs: []string{"a", "b", "c"},
+		},
+		"error mid-stream": {
+			opts:    ListOptions{PageSize: 2},
+			failing: []string{"2"},
+			wantIDs: []string{"a", "b"},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := pagedServer(t, pages, tt.failing...)
+
+			c, err := New(server.URL, testToken(t))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			ctx := context.Background()
+			it := c.IterateRuntimeGroups(tt.opts)
+
+			var ids []string
+			for it.Next(ctx) {
+				ids = append(ids, it.Value().ID)
+			}
+
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Fatalf("expected ids %v, got %v", tt.wantIDs, ids)
+			}
+
+			var apiErr *APIError
+			if tt.wantErr != errors.As(it.Err(), &apiErr) {
+				t.Fatalf("expected error %t, got %v", tt.wantErr, it.Err())
+			}
+
+			if it.Next(ctx) {
+				t.Fatal("expected Next to keep returning false")
+			}
+		})
+	}
+}
+
+func TestRuntimeGroupIteratorEmpty(t *testing.T) {
+	server := pagedServer(t, map[string]string{"1": `{"meta":{"page":{"number":1,"size":10}},"data":[]}`})
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	it := c.IterateRuntimeGroups(ListOptions{})
+	if it.Next(context.Background()) || it.Err() != nil {
+		t.Fatalf("expected no runtime groups and no error, got %v", it.Err())
+	}
+}
@@ -0,0 +1,156 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// jsonPatchContentType is the Content-Type of PatchRuntimeGroup requests.
+	jsonPatchContentType = "application/json-patch+json"
+
+	// patchRuntimeGroupMethod is the HTTP method for patching a runtime group.
+	patchRuntimeGroupMethod = http.MethodPatch
+)
+
+// The JSON Patch operations accepted by PatchRuntimeGroup.
+const (
+	PatchOpAdd     = "add"
+	PatchOpRemove  = "remove"
+	PatchOpReplace = "replace"
+)
+
+// PatchOp is a JSON Patch (RFC 6902) operation on a runtime group. Path is a
+// JSON Pointer (RFC 6901), e.g. "/labels/env". Value is ignored by remove
+// operations.
+type PatchOp struct {
+	Op    string
+	Path  string
+	Value any
+}
+
+// MarshalJSON omits the value of remove operations and keeps the zero value
+// of the others, e.g. an empty description.
+func (o PatchOp) MarshalJSON() ([]byte, error) {
+	if o.Op == PatchOpRemove {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{o.Op, o.Path})
+	}
+
+	return json.Marshal(struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value any    `json:"value"`
+	}{o.Op, o.Path, o.Value})
+}
+
+// SetLabelOp returns the operation setting the label key to value, adding it
+// when missing.
+func SetLabelOp(key, value string) PatchOp {
+	return PatchOp{Op: PatchOpAdd, Path: labelPointer(key), Value: value}
+}
+
+// RemoveLabelOp returns the operation removing the label key. The API rejects
+// the patch when the runtime group has no such label.
+func RemoveLabelOp(key string) PatchOp {
+	return PatchOp{Op: PatchOpRemove, Path: labelPointer(key)}
+}
+
+// ReplaceNameOp returns the operation renaming the runtime group.
+func ReplaceNameOp(name string) PatchOp {
+	return PatchOp{Op: PatchOpReplace, Path: "/name", Value: name}
+}
+
+// ReplaceDescriptionOp returns the operation replacing the description of the
+// runtime group.
+func ReplaceDescriptionOp(description string) PatchOp {
+	return PatchOp{Op: PatchOpReplace, Path: "/description", Value: description}
+}
+
+// labelPointer returns the JSON Pointer of the label key, escaping the "~"
+// and "/" it may hold.
+func labelPointer(key string) string {
+	return "/labels/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+// validatePatch checks the operations before they are sent, including the
+// keys of the labels they set.
+func validatePatch(ops []PatchOp) error {
+	if len(ops) == 0 {
+		return fmt.Errorf("at least one operation is required")
+	}
+
+	for i, op := range ops {
+		switch op.Op {
+		case PatchOpAdd, PatchOpRemove, PatchOpReplace:
+		default:
+			return fmt.Errorf("operation %d: op %q is not one of %q, %q, %q", i, op.Op, PatchOpAdd, PatchOpRemove, PatchOpReplace)
+		}
+
+		if !strings.HasPrefix(op.Path, "/") {
+			return fmt.Errorf("operation %d: path %q is not a JSON Pointer", i, op.Path)
+		}
+
+		if strings.HasPrefix(op.Path, "/labels/") && op.Op != PatchOpRemove {
+			key := strings.NewReplacer("~1", "/", "~0", "~").Replace(strings.TrimPrefix(op.Path, "/labels/"))
+			if err := validateLabels(map[string]string{key: ""}); err != nil {
+				return fmt.Errorf("operation %d: %w", i, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// PatchRuntimeGroup sends a PATCH request applying the JSON Patch (RFC 6902)
+// operations to the runtime group with the given id, e.g. built with
+// SetLabelOp and RemoveLabelOp. Unlike UpdateRuntimeGroup, only the fields the
+// operations name are touched, and the default labels are not merged in. The
+// API applies the operations atomically.
+func (c *Client) PatchRuntimeGroup(ctx context.Context, id string, ops []PatchOp) error {
+	defer c.locks.lock(id)()
+
+	c.cache.invalidate(id)
+	defer c.cache.invalidate(id)
+
+	if err := validatePatch(ops); err != nil {
+		return c.wrap("validating patch", err)
+	}
+
+	requestBodyBytes, err := json.Marshal(ops)
+	if err != nil {
+		return c.wrap("serializing request body", err)
+	}
+
+	endpoint, err := c.endpoint(OperationPatchRuntimeGroup, runtimeGroupEndpoint, url.PathEscape(id))
+	if err != nil {
+		return c.wrap("joining base URL and endpoint", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, patchRuntimeGroupMethod, endpoint, bytes.NewBuffer(requestBodyBytes))
+	if err != nil {
+		return c.wrap("creating HTTP request", err)
+	}
+
+	req.Header.Set("Content-Type", jsonPatchContentType)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return c.wrap("making HTTP request", err)
+	}
+	defer resp.Body.Close()
+
+	// Check the HTTP response status code.
+	if err := c.checkResponse(resp); err != nil {
+		return c.wrap("checking status code", err)
+	}
+
+	return nil
+}
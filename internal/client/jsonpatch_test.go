@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPatchRuntimeGroup(t *testing.T) {
+	tests := map[string]struct {
+		ops      []PatchOp
+		wantBody string
+	}{
+		"set label": {
+			ops:      []PatchOp{SetLabelOp("env", "prod")},
+			wantBody: `[{"op":"add","path":"/labels/env","value":"prod"}]`,
+		},
+		"remove label": {
+			ops:      []PatchOp{RemoveLabelOp("env")},
+			wantBody: `[{"op":"remove","path":"/labels/env"}]`,
+		},
+		"escaped label key": {
+			ops:      []PatchOp{SetLabelOp("app/tier~1", "web")},
+			wantBody: `[{"op":"add","path":"/labels/app~1tier~01","value":"web"}]`,
+		},
+		"name and empty description": {
+			ops:      []PatchOp{ReplaceNameOp("renamed"), ReplaceDescriptionOp("")},
+			wantBody: `[{"op":"replace","path":"/name","value":"renamed"},{"op":"replace","path":"/description","value":""}]`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPatch || r.URL.Path != "/runtime-groups/rg-1" {
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+				}
+
+				if contentType := r.Header.Get("Content-Type"); contentType != "application/json-patch+json" {
+					t.Errorf("expected the JSON Patch content type, got %s", contentType)
+				}
+
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Errorf("reading request body: %s", err)
+				}
+
+				if string(body) != tt.wantBody {
+					t.Errorf("expected body %s, got %s", tt.wantBody, body)
+				}
+
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			c, err := New(server.URL, testToken(t))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if err := c.PatchRuntimeGroup(context.Background(), "rg-1", tt.ops); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestPatchRuntimeGroupInvalid(t *testing.T) {
+	tests := map[string][]PatchOp{
+		"no operations":      nil,
+		"unknown op":         {{Op: "move", Path: "/name"}},
+		"relative path":      {{Op: PatchOpReplace, Path: "name", Value: "renamed"}},
+		"reserved label key": {SetLabelOp("konnect.owner", "someone")},
+	}
+
+	for name, ops := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := New("http://127.0.0.1:0", testToken(t))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if err := c.PatchRuntimeGroup(context.Background(), "rg-1", ops); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,310 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const (
+	// runtimeGroupLabelsEndpoint is the subresource of a runtime group holding
+	// its labels.
+	runtimeGroupLabelsEndpoint = "labels"
+
+	// updateRuntimeGroupLabelsMethod is the HTTP method for updating the labels
+	// of a runtime group.
+	updateRuntimeGroupLabelsMethod = http.MethodPatch
+)
+
+// reservedLabelPrefixes are the label key prefixes reserved by Konnect.
+var reservedLabelPrefixes = []string{"kong", "konnect", "mesh", "kic"}
+
+// ReservedLabelPrefixes returns the label key prefixes reserved by Konnect,
+// which the labels sent by the client cannot start with.
+func ReservedLabelPrefixes() []string {
+	return append([]string(nil), reservedLabelPrefixes...)
+}
+
+// ReservedLabelPrefix returns the reserved prefix key starts with, if any.
+func ReservedLabelPrefix(key string) (string, bool) {
+	for _, prefix := range reservedLabelPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return prefix, true
+		}
+	}
+
+	return "", false
+}
+
+// validateLabels checks that no label key starts with a reserved prefix. Keys
+// are checked in order, so the error names the same key on every call.
+func validateLabels(labels map[string]string) error {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if prefix, ok := ReservedLabelPrefix(k); ok {
+			return fmt.Errorf("label key %q cannot start with the reserved prefix %q", k, prefix)
+		}
+	}
+
+	return nil
+}
+
+// DefaultLabels returns a copy of the labels merged into every created
+// runtime group, with their templates unexpanded, see WithDefaultLabels.
+func (c *Client) DefaultLabels() map[string]string {
+	return copyLabels(c.defaultLabels)
+}
+
+// DefaultLabelsFor returns the default labels of the runtime group with the
+// given name and cluster type, their templates expanded.
+func (c *Client) DefaultLabelsFor(name string, clusterType ClusterType) map[string]string {
+	// The templates were checked by WithDefaultLabels.
+	expanded, _ := expandLabelTemplates(c.defaultLabels, labelVars{Name: name, ClusterType: clusterType})
+	return expanded
+}
+
+// mergeDefaultLabels returns labels merged over the client default labels of
+// the runtime group described by vars. Labels win on key conflicts.
+func (c *Client) mergeDefaultLabels(labels map[string]string, vars labelVars) map[string]string {
+	if len(c.defaultLabels) == 0 {
+		return labels
+	}
+
+	merged := c.DefaultLabelsFor(vars.Name, vars.ClusterType)
+	for k, v := range labels {
+		merged[k] = v
+	}
+
+	return merged
+}
+
+// labelVars are the runtime group attributes default label values may
+// reference, see WithDefaultLabels.
+type labelVars struct {
+	Name        string
+	ClusterType ClusterType
+}
+
+// expandLabelTemplates returns a copy of labels with the $name and
+// $cluster_type tokens of their values replaced by vars.
+func expandLabelTemplates(labels map[string]string, vars labelVars) (map[string]string, error) {
+	expanded := make(map[string]string, len(labels))
+	for k, v := range labels {
+		value, err := expandLabelTemplate(v, vars)
+		if err != nil {
+			return nil, fmt.Errorf("label %q: %w", k, err)
+		}
+		expanded[k] = value
+	}
+
+	return expanded, nil
+}
+
+// expandLabelTemplate replaces the $name and $cluster_type tokens of value by
+// vars, and $$ by $. A token is a $ followed by lowercase letters and
+// underscores, any other token is an error.
+func expandLabelTemplate(value string, vars labelVars) (string, error) {
+	if !strings.Contains(value, "$") {
+		return value, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(value); {
+		if value[i] != '$' {
+			b.WriteByte(value[i])
+			i++
+			continue
+		}
+
+		if strings.HasPrefix(value[i:], "$$") {
+			b.WriteByte('$')
+			i += 2
+			continue
+		}
+
+		end := i + 1
+		for end < len(value) && (value[end] == '_' || 'a' <= value[end] && value[end] <= 'z') {
+			end++
+		}
+
+		switch token := value[i:end]; token {
+		case "$name":
+			b.WriteString(vars.Name)
+		case "$cluster_type":
+			b.WriteString(string(vars.ClusterType))
+		default:
+			return "", fmt.Errorf("unknown token %q in value %q, only $name and $cluster_type are supported, $$ escapes $", token, value)
+		}
+		i = end
+	}
+
+	return b.String(), nil
+}
+
+// ValidateLabelTemplate checks that value is a default label value
+// WithDefaultLabels accepts, i.e. holds no token but $name, $cluster_type and
+// $$.
+func ValidateLabelTemplate(value string) error {
+	_, err := expandLabelTemplate(value, labelVars{})
+	return err
+}
+
+// hasLabelTemplates reports whether a value of labels holds a token.
+func hasLabelTemplates(labels map[string]string) bool {
+	for _, v := range labels {
+		if strings.Contains(v, "$") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// copyLabels returns a shallow copy of labels.
+func copyLabels(labels map[string]string) map[string]string {
+	copied := make(map[string]string, len(labels))
+	for k, v := range labels {
+		copied[k] = v
+	}
+
+	return copied
+}
+
+// UpdateRuntimeGroupLabels sets the labels of the runtime group with the given
+// id, merged over the client default labels, leaving its other fields
+// untouched. Nil labels remove them all. The labels are sent alone to the
+// labels subresource, or else, when the API does not expose it, with the name
+// and description of the runtime group read beforehand in a full PATCH
+// request. The fallback is remembered for the next calls.
+func (c *Client) UpdateRuntimeGroupLabels(ctx context.Context, id string, labels map[string]string) (*CreateRuntimeGroupResponse, error) {
+	defer c.locks.lock(id)()
+
+	if err := validateLabels(labels); err != nil {
+		return nil, c.wrap("validating labels", err)
+	}
+
+	// The labels subresource knows nothing of the runtime group, the templates
+	// of the default labels are expanded with the runtime group read first.
+	var vars labelVars
+	if hasLabelTemplates(c.defaultLabels) && !c.noLabelsEndpoint.Load() {
+		group, err := c.GetRuntimeGroup(ctx, id)
+		if err != nil {
+			return nil, c.wrap("reading runtime group", err)
+		}
+		vars = labelVars{Name: group.Name, ClusterType: group.Config.ClusterType}
+	}
+
+	if !c.noLabelsEndpoint.Load() {
+		group, err := c.patchRuntimeGroupLabels(ctx, id, labels, vars)
+		if !errors.Is(err, ErrNotFound) && !errors.Is(err, ErrNotSupported) {
+			return group, err
+		}
+	}
+
+	// A missing runtime group fails the full update too.
+	group, err := c.GetRuntimeGroup(ctx, id)
+	if err != nil {
+		return nil, c.wrap("reading runtime group", err)
+	}
+
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	group, err = c.updateRuntimeGroup(ctx, id, UpdateRuntimeGroupRequest{
+		Name:        group.Name,
+		Description: group.Description,
+		ClusterType: group.Config.ClusterType,
+		Labels:      labels,
+		PriorLabels: withoutReservedLabels(group.Labels),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.noLabelsEndpoint.Store(true)
+
+	return group, nil
+}
+
+// patchRuntimeGroupLabels sends a PATCH request setting the labels of the
+// runtime group with the given id to its labels subresource.
+func (c *Client) patchRuntimeGroupLabels(ctx context.Context, id string, labels map[string]string, vars labelVars) (*CreateRuntimeGroupResponse, error) {
+	c.cache.invalidate(id)
+	defer c.cache.invalidate(id)
+
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	requestBodyBytes, contentType, err := c.encoder(c.mergeDefaultLabels(labels, vars))
+	if err != nil {
+		return nil, c.wrap("serializing request body", err)
+	}
+
+	endpoint, err := c.endpoint(OperationUpdateRuntimeGroupLabels, runtimeGroupEndpoint, url.PathEscape(id), runtimeGroupLabelsEndpoint)
+	if err != nil {
+		return nil, c.wrap("joining base URL and endpoint", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, updateRuntimeGroupLabelsMethod, endpoint, bytes.NewBuffer(requestBodyBytes))
+	if err != nil {
+		return nil, c.wrap("creating HTTP request", err)
+	}
+
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, c.wrap("making HTTP request", err)
+	}
+	defer resp.Body.Close()
+
+	// Check the HTTP response status code.
+	if err := c.checkResponse(resp); err != nil {
+		return nil, c.wrap("checking status code", err)
+	}
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, c.wrap("reading response body", err)
+	}
+
+	// The subresource may answer with the labels alone, the runtime group is
+	// read again unless the response is the runtime group itself.
+	var ref struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(raw, &ref); err != nil || ref.ID != id {
+		return c.GetRuntimeGroup(req.Context(), id)
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(raw))
+
+	return c.readRuntimeGroup(req.Context(), resp, id)
+}
+
+// withoutReservedLabels returns a copy of labels without the keys under the
+// reserved prefixes, which Konnect manages itself.
+func withoutReservedLabels(labels map[string]string) map[string]string {
+	filtered := make(map[string]string, len(labels))
+	for k, v := range labels {
+		if _, reserved := ReservedLabelPrefix(k); !reserved {
+			filtered[k] = v
+		}
+	}
+
+	return filtered
+}
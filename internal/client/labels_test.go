@@ -0,0 +1,371 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCreateRuntimeGroupDefaultLabels(t *testing.T) {
+	tests := map[string]struct {
+		defaults map[string]string
+		labels   map[string]string
+		want     map[string]string
+	}{
+		"merged": {
+			defaults: map[string]string{"team": "platform"},
+			labels:   map[string]string{"env": "dev"},
+			want:     map[string]string{"team": "platform", "env": "dev"},
+		},
+		"resource labels win on conflicts": {
+			defaults: map[string]string{"team": "platform", "env": "prod"},
+			labels:   map[string]string{"env": "dev"},
+			want:     map[string]string{"team": "platform", "env": "dev"},
+		},
+		"defaults only": {
+			defaults: map[string]string{"team": "platform"},
+			want:     map[string]string{"team": "platform"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body CreateRuntimeGroupRequest
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("decoding request body: %s", err)
+				}
+
+				if !reflect.DeepEqual(body.Labels, test.want) {
+					t.Errorf("expected labels %v, got %v", test.want, body.Labels)
+				}
+
+				w.WriteHeader(http.StatusCreated)
+				fmt.Fprint(w, `{"id":"rg-1","name":"test"}`)
+			}))
+			defer server.Close()
+
+			c, err := New(server.URL, testToken(t), WithDefaultLabels(test.defaults))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if _, err := c.CreateRuntimeGroup(CreateRuntimeGroupRequest{Name: "test", Labels: test.labels}); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestDefaultLabelTemplates(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body CreateRuntimeGroupRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding request body: %s", err)
+		}
+		got = body.Labels
+
+		fmt.Fprint(w, `{"id":"rg-1","name":"test"}`)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t), WithDefaultLabels(map[string]string{
+		"owner": "$name-team",
+		"kind":  "$cluster_type",
+		"cost":  "$$5",
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := map[string]string{
+		"owner": "edge-team",
+		"kind":  "CLUSTER_TYPE_HYBRID",
+		"cost":  "$5",
+		"env":   "dev",
+	}
+
+	if _, err := c.CreateRuntimeGroup(CreateRuntimeGroupRequest{Name: "edge", ClusterType: ClusterTypeHybrid, Labels: map[string]string{"env": "dev"}}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected create labels %v, got %v", want, got)
+	}
+
+	// Updates expand the templates the same way.
+	if _, err := c.UpdateRuntimeGroup(context.Background(), "rg-1", UpdateRuntimeGroupRequest{Name: "edge", ClusterType: ClusterTypeHybrid, Labels: map[string]string{"env": "dev"}}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected update labels %v, got %v", want, got)
+	}
+
+	if defaults := c.DefaultLabelsFor("edge", ClusterTypeHybrid); defaults["owner"] != "edge-team" {
+		t.Fatalf("expected the expanded default labels, got %v", defaults)
+	}
+}
+
+func TestDefaultLabelTemplatesUnknownToken(t *testing.T) {
+	for _, value := range []string{"$namespace", "${name}", "$org", "cost: 5$"} {
+		_, err := New("https://example.com", testToken(t), WithDefaultLabels(map[string]string{"owner": value}))
+		if err == nil || !strings.Contains(err.Error(), "unknown token") {
+			t.Errorf("expected an unknown token error for %q, got %v", value, err)
+		}
+	}
+}
+
+func TestDefaultLabelsReturnsCopy(t *testing.T) {
+	c, err := New("https://example.com", testToken(t), WithDefaultLabels(map[string]string{"team": "platform"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	c.DefaultLabels()["team"] = "changed"
+
+	if got := c.DefaultLabels()["team"]; got != "platform" {
+		t.Fatalf("expected the default labels to be unchanged, got %q", got)
+	}
+}
+
+func TestUpdateRuntimeGroupLabels(t *testing.T) {
+	tests := map[string]struct {
+		labelsEndpoint bool
+		wantRequests   []string
+		wantBody       string
+	}{
+		"labels subresource": {
+			labelsEndpoint: true,
+			wantRequests:   []string{"PATCH /runtime-groups/rg-1/labels"},
+			wantBody:       `{"env":"prod"}`,
+		},
+		"full update fallback": {
+			wantRequests: []string{
+				"PATCH /runtime-groups/rg-1/labels",
+				"GET /runtime-groups/rg-1",
+				"PATCH /runtime-groups/rg-1",
+			},
+			wantBody: `{"name":"test","description":"kept","labels":{"env":"prod"}}`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var requests []string
+			var body []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+
+				switch {
+				case r.URL.Path == "/runtime-groups/rg-1/labels" && !test.labelsEndpoint:
+					w.WriteHeader(http.StatusNotFound)
+					return
+				case r.Method == http.MethodGet:
+					fmt.Fprint(w, `{"id":"rg-1","name":"test","description":"kept","labels":{"env":"dev"}}`)
+					return
+				}
+
+				body, _ = io.ReadAll(r.Body)
+				fmt.Fprint(w, `{"id":"rg-1","name":"test","description":"kept","labels":{"env":"prod"}}`)
+			}))
+			defer server.Close()
+
+			c, err := New(server.URL, testToken(t))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			group, err := c.UpdateRuntimeGroupLabels(context.Background(), "rg-1", map[string]string{"env": "prod"})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !reflect.DeepEqual(requests, test.wantRequests) {
+				t.Fatalf("expected requests %v, got %v", test.wantRequests, requests)
+			}
+
+			if string(body) != test.wantBody {
+				t.Fatalf("expected body %s, got %s", test.wantBody, body)
+			}
+
+			if group.Labels["env"] != "prod" {
+				t.Fatalf("unexpected runtime group: %+v", group)
+			}
+
+			// The fallback is remembered, the labels subresource is not tried again.
+			requests = nil
+			if _, err := c.UpdateRuntimeGroupLabels(context.Background(), "rg-1", map[string]string{"env": "prod"}); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if tried := requests[0] == "PATCH /runtime-groups/rg-1/labels"; tried != test.labelsEndpoint {
+				t.Fatalf("expected the labels subresource tried %t, got requests %v", test.labelsEndpoint, requests)
+			}
+		})
+	}
+}
+
+func TestUpdateRuntimeGroupLabelsResponse(t *testing.T) {
+	tests := map[string]struct {
+		response     string
+		wantRequests []string
+	}{
+		"runtime group": {
+			response:     `{"id":"rg-1","name":"test","labels":{"env":"prod"},"config":{"control_plane_endpoint":"https://cp.example.com"}}`,
+			wantRequests: []string{"PATCH /runtime-groups/rg-1/labels"},
+		},
+		"labels alone": {
+			response:     `{"env":"prod"}`,
+			wantRequests: []string{"PATCH /runtime-groups/rg-1/labels", "GET /runtime-groups/rg-1"},
+		},
+		"empty": {
+			wantRequests: []string{"PATCH /runtime-groups/rg-1/labels", "GET /runtime-groups/rg-1"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var requests []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+
+				if r.Method == http.MethodGet {
+					fmt.Fprint(w, `{"id":"rg-1","name":"test","labels":{"env":"prod"},"config":{"control_plane_endpoint":"https://cp.example.com"}}`)
+					return
+				}
+
+				fmt.Fprint(w, test.response)
+			}))
+			defer server.Close()
+
+			c, err := New(server.URL, testToken(t))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			group, err := c.UpdateRuntimeGroupLabels(context.Background(), "rg-1", map[string]string{"env": "prod"})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !reflect.DeepEqual(requests, test.wantRequests) {
+				t.Fatalf("expected requests %v, got %v", test.wantRequests, requests)
+			}
+
+			if group.ID != "rg-1" || group.Name != "test" || group.Config.ControlPlaneEndpoint != "https://cp.example.com" {
+				t.Fatalf("expected the full runtime group, got %+v", group)
+			}
+		})
+	}
+}
+
+func TestUpdateRuntimeGroupLabelsFallbackReservedLabels(t *testing.T) {
+	var body map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/runtime-groups/rg-1/labels":
+			w.WriteHeader(http.StatusNotFound)
+			return
+		case r.Method == http.MethodGet:
+			fmt.Fprint(w, `{"id":"rg-1","name":"test","labels":{"env":"dev","konnect.created_by":"ui"}}`)
+			return
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding request body: %s", err)
+		}
+		fmt.Fprint(w, `{"id":"rg-1","name":"test","labels":{"env":"prod","konnect.created_by":"ui"}}`)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t), WithMergePatch(true))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := c.UpdateRuntimeGroupLabels(context.Background(), "rg-1", map[string]string{"env": "prod"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// The reserved label is left to Konnect rather than removed with a null.
+	if want := map[string]any{"env": "prod"}; !reflect.DeepEqual(body["labels"], want) {
+		t.Fatalf("expected labels %v, got %v", want, body["labels"])
+	}
+}
+
+func TestUpdateRuntimeGroupLabelsNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := c.UpdateRuntimeGroupLabels(context.Background(), "rg-1", nil); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	if c.noLabelsEndpoint.Load() {
+		t.Fatal("expected a missing runtime group not to disable the labels subresource")
+	}
+}
+
+func TestReservedLabelKeys(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"id":"rg-1","name":"test"}`)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, prefix := range ReservedLabelPrefixes() {
+		key := prefix + ".owner"
+		labels := map[string]string{"env": "dev", key: "someone"}
+
+		t.Run(prefix, func(t *testing.T) {
+			calls := map[string]func() error{
+				"create": func() error {
+					_, err := c.CreateRuntimeGroup(CreateRuntimeGroupRequest{Name: "test", Labels: labels})
+					return err
+				},
+				"update": func() error {
+					_, err := c.UpdateRuntimeGroup(context.Background(), "rg-1", UpdateRuntimeGroupRequest{Name: "test", Labels: labels})
+					return err
+				},
+				"update labels": func() error {
+					_, err := c.UpdateRuntimeGroupLabels(context.Background(), "rg-1", labels)
+					return err
+				},
+			}
+
+			for name, call := range calls {
+				err := call()
+				if err == nil || !strings.Contains(err.Error(), strconv.Quote(key)) {
+					t.Fatalf("%s: expected an error naming %q, got %v", name, key, err)
+				}
+			}
+		})
+	}
+
+	if requests != 0 {
+		t.Fatalf("expected no request to be sent, got %d", requests)
+	}
+}
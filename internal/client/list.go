@@ -0,0 +1,474 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sort orders of ListOptions.
+const (
+	SortAscending  = "asc"
+	SortDescending = "desc"
+)
+
+// Related objects of a runtime group expanded with GetOptions or ListOptions.
+const (
+	IncludeConfig = "config"
+	IncludeStatus = "status"
+)
+
+// fieldsParam is the sparse fieldset query parameter of runtime groups.
+const fieldsParam = "fields[runtime_group]"
+
+// GetOptions represents the query parameters for fetching a runtime group.
+type GetOptions struct {
+	// Include expands the given related objects of the runtime group in the
+	// response, e.g. IncludeStatus. The APIs not supporting them ignore it.
+	Include []string
+	// Fields restricts the response to the given fields of the runtime group,
+	// e.g. "name" and "labels", to reduce its size. The fields left out are
+	// zero-valued in the returned runtime group, so it must not populate
+	// computed attributes: they are unknown rather than empty. Empty returns
+	// every field.
+	Fields []string
+}
+
+// values converts the options to the query parameters of the request.
+func (o GetOptions) values() url.Values {
+	values := url.Values{}
+
+	if len(o.Include) > 0 {
+		values.Set("include", strings.Join(o.Include, ","))
+	}
+
+	if len(o.Fields) > 0 {
+		values.Set(fieldsParam, strings.Join(o.Fields, ","))
+	}
+
+	return values
+}
+
+// includedIn reports whether group holds the related objects of o.Include.
+func (o GetOptions) includedIn(group *CreateRuntimeGroupResponse) bool {
+	for _, include := range o.Include {
+		switch include {
+		case IncludeConfig:
+			// The config is always returned.
+		case IncludeStatus:
+			if group.Status == nil {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// sortFields are the fields runtime groups can be sorted by.
+var sortFields = []string{"name", "created_at", "updated_at"}
+
+// ListOptions represents the query parameters for listing runtime groups.
+type ListOptions struct {
+	// PageSize is the maximum number of items to include per page.
+	PageSize int
+	// PageNumber determines which page of the entities to retrieve.
+	PageNumber int
+	// Limit stops ListAllRuntimeGroups once that many items are gathered.
+	// Zero means unlimited. It is not sent to the API.
+	Limit int
+
+	// Name keeps the runtime groups with exactly the given name.
+	Name string
+	// ClusterTypes keeps the runtime groups of any of the given cluster types.
+	// The API filters on a single cluster type, so ListAllRuntimeGroups and
+	// CountRuntimeGroups send one query per cluster type, and ListRuntimeGroups
+	// accepts at most one.
+	ClusterTypes []ClusterType
+	// Labels keeps the runtime groups matching any of the given "key:value"
+	// label selectors, or "key" to check its existence.
+	Labels []string
+	// IDs keeps the runtime groups with any of the given ids.
+	IDs []string
+	// ModifiedSince keeps the runtime groups updated at or after the given
+	// time, sent as an RFC 3339 timestamp in UTC. All runtime groups are
+	// returned by the APIs not supporting the filter.
+	ModifiedSince time.Time
+
+	// SortBy asks the API to sort the runtime groups by "name", "created_at"
+	// or "updated_at". ListAllRuntimeGroups sorts them again, so that the order
+	// holds with servers ignoring it. Empty sorts by name, then id.
+	SortBy string
+	// SortOrder is SortAscending, the default, or SortDescending.
+	SortOrder string
+
+	// Include expands the given related objects of the runtime groups, see
+	// GetOptions.
+	Include []string
+	// Fields restricts the runtime groups to the given fields, see GetOptions.
+	Fields []string
+}
+
+// validate checks the sort options.
+func (o ListOptions) validate() error {
+	if o.SortBy != "" {
+		valid := false
+		for _, field := range sortFields {
+			valid = valid || o.SortBy == field
+		}
+
+		if !valid {
+			return fmt.Errorf("unknown sort field %q, expected one of %s", o.SortBy, strings.Join(sortFields, ", "))
+		}
+	}
+
+	if o.SortOrder != "" && o.SortOrder != SortAscending && o.SortOrder != SortDescending {
+		return fmt.Errorf("unknown sort order %q, expected %s or %s", o.SortOrder, SortAscending, SortDescending)
+	}
+
+	return nil
+}
+
+// values converts the options to the query parameters of the request.
+func (o ListOptions) values() url.Values {
+	values := url.Values{}
+
+	if o.PageSize > 0 {
+		values.Set("page[size]", strconv.Itoa(o.PageSize))
+	}
+
+	if o.PageNumber > 0 {
+		values.Set("page[number]", strconv.Itoa(o.PageNumber))
+	}
+
+	if o.Name != "" {
+		values.Set("filter[name][eq]", o.Name)
+	}
+
+	// filter[cluster_type] is an equality filter, see the FilterByClusterType
+	// parameters of the spec: several cluster types are queried separately.
+	if len(o.ClusterTypes) == 1 {
+		values.Set("filter[cluster_type]", string(o.ClusterTypes[0]))
+	}
+
+	// The label selectors are joined by commas in the labels parameter, e.g.
+	// labels=key:value,existCheck, see FilterByLabels in the spec.
+	if len(o.Labels) > 0 {
+		values.Set("labels", strings.Join(o.Labels, ","))
+	}
+
+	if len(o.IDs) > 0 {
+		values.Set("filter[id]", strings.Join(o.IDs, ","))
+	}
+
+	if !o.ModifiedSince.IsZero() {
+		values.Set("filter[updated_at][gte]", o.ModifiedSince.UTC().Format(time.RFC3339))
+	}
+
+	if len(o.Include) > 0 {
+		values.Set("include", strings.Join(o.Include, ","))
+	}
+
+	if len(o.Fields) > 0 {
+		values.Set(fieldsParam, strings.Join(o.Fields, ","))
+	}
+
+	// Descending sorts are prefixed with a minus, e.g. sort=-created_at.
+	if o.SortBy != "" {
+		field := o.SortBy
+		if o.SortOrder == SortDescending {
+			field = "-" + field
+		}
+		values.Set("sort", field)
+	}
+
+	return values
+}
+
+// PageMeta represents the pagination metadata returned by the API.
+type PageMeta struct {
+	Number int `json:"number"`
+	Size   int `json:"size"`
+	// Total is nil when the server does not report the total number of objects.
+	Total *int `json:"total"`
+}
+
+// PaginatedMeta represents the meta object of a paginated response.
+type PaginatedMeta struct {
+	Page PageMeta `json:"page"`
+}
+
+// ListRuntimeGroupsResponse represents the response from listing runtime groups.
+type ListRuntimeGroupsResponse struct {
+	Meta PaginatedMeta                `json:"meta"`
+	Data []CreateRuntimeGroupResponse `json:"data"`
+}
+
+// ListRuntimeGroups sends a GET request to fetch a single page of runtime groups.
+func (c *Client) ListRuntimeGroups(ctx context.Context, opts ListOptions) (*ListRuntimeGroupsResponse, error) {
+	if err := opts.validate(); err != nil {
+		return nil, c.wrap("validating list options", err)
+	}
+
+	if len(opts.ClusterTypes) > 1 {
+		return nil, c.wrap("validating list options", fmt.Errorf("a single page lists one cluster type, got %d, use ListAllRuntimeGroups", len(opts.ClusterTypes)))
+	}
+
+	page, err := c.listRuntimeGroups(ctx, opts)
+	if err != nil && !opts.ModifiedSince.IsZero() && filterUnsupported(err) {
+		opts.ModifiedSince = time.Time{}
+		return c.listRuntimeGroups(ctx, opts)
+	}
+
+	return page, err
+}
+
+// listRuntimeGroups fetches the page of runtime groups selected by opts.
+func (c *Client) listRuntimeGroups(ctx context.Context, opts ListOptions) (*ListRuntimeGroupsResponse, error) {
+
+	endpoint, err := c.endpoint(OperationListRuntimeGroups, runtimeGroupEndpoint)
+	if err != nil {
+		return nil, c.wrap("joining base URL and endpoint", err)
+	}
+
+	if query := opts.values().Encode(); query != "" {
+		endpoint += "?" + query
+	}
+
+	req, err := http.NewRequestWithContext(ctx, listRuntimeGroupsMethod, endpoint, nil)
+	if err != nil {
+		return nil, c.wrap("creating HTTP request", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, c.wrap("making HTTP request", err)
+	}
+	defer resp.Body.Close()
+
+	// Check the HTTP response status code.
+	if err := c.checkResponse(resp); err != nil {
+		return nil, c.wrap("checking status code", err)
+	}
+
+	var listResponse ListRuntimeGroupsResponse
+	if _, err := readJSON(resp.Body, &listResponse); err != nil {
+		return nil, c.wrap("decoding response JSON", err)
+	}
+
+	if err := c.validateResponse(&listResponse); err != nil {
+		return nil, err
+	}
+
+	return &listResponse, nil
+}
+
+// ListAllRuntimeGroups fetches every page of runtime groups starting from the
+// page in opts, stopping early once opts.Limit items are gathered. The
+// returned meta is the one reported with the first page. The runtime groups are
+// sorted as requested by opts, by name by default, then id, so that generated
+// configuration is stable across calls whatever the order of the API.
+func (c *Client) ListAllRuntimeGroups(ctx context.Context, opts ListOptions) (*ListRuntimeGroupsResponse, error) {
+	all, err := c.listAllRuntimeGroups(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	sortRuntimeGroups(all.Data, opts.SortBy, opts.SortOrder == SortDescending)
+
+	return all, nil
+}
+
+// listAllRuntimeGroups gathers the pages of ListAllRuntimeGroups in API order.
+func (c *Client) listAllRuntimeGroups(ctx context.Context, opts ListOptions) (*ListRuntimeGroupsResponse, error) {
+	if len(opts.ClusterTypes) > 1 {
+		return c.listAllClusterTypes(ctx, opts)
+	}
+
+	if opts.PageNumber < 1 {
+		opts.PageNumber = 1
+	}
+
+	var all *ListRuntimeGroupsResponse
+	for {
+		page, err := c.ListRuntimeGroups(ctx, opts)
+		if err != nil {
+			return nil, c.wrap("listing page "+strconv.Itoa(opts.PageNumber), err)
+		}
+
+		if all == nil {
+			all = &ListRuntimeGroupsResponse{Meta: page.Meta}
+		}
+		all.Data = append(all.Data, page.Data...)
+
+		if opts.Limit > 0 && len(all.Data) >= opts.Limit {
+			all.Data = all.Data[:opts.Limit]
+			return all, nil
+		}
+
+		if lastPage(page, opts.PageSize, len(all.Data)) {
+			return all, nil
+		}
+
+		opts.PageNumber++
+	}
+}
+
+// listAllClusterTypes gathers the runtime groups of every cluster type of
+// opts, queried one after the other. The meta is the one of the first cluster
+// type, with the total of all of them when every one reports it.
+func (c *Client) listAllClusterTypes(ctx context.Context, opts ListOptions) (*ListRuntimeGroupsResponse, error) {
+	var all *ListRuntimeGroupsResponse
+	seen := make(map[string]bool)
+	total := 0
+	totalKnown := true
+
+	for _, clusterType := range opts.ClusterTypes {
+		typeOpts := opts
+		typeOpts.ClusterTypes = []ClusterType{clusterType}
+
+		groups, err := c.listAllRuntimeGroups(ctx, typeOpts)
+		if err != nil {
+			return nil, c.wrap("listing cluster type "+string(clusterType), err)
+		}
+
+		if all == nil {
+			all = &ListRuntimeGroupsResponse{Meta: groups.Meta}
+		}
+
+		// Servers ignoring the filter return the same runtime groups for every
+		// cluster type.
+		for _, group := range groups.Data {
+			if !seen[group.ID] {
+				seen[group.ID] = true
+				all.Data = append(all.Data, group)
+			}
+		}
+
+		if groups.Meta.Page.Total != nil {
+			total += *groups.Meta.Page.Total
+		} else {
+			totalKnown = false
+		}
+
+		if opts.Limit > 0 && len(all.Data) >= opts.Limit {
+			all.Data = all.Data[:opts.Limit]
+			totalKnown = false
+			break
+		}
+	}
+
+	all.Meta.Page.Total = nil
+	if totalKnown {
+		all.Meta.Page.Total = &total
+	}
+
+	return all, nil
+}
+
+// CountRuntimeGroups returns the number of runtime groups selected by the
+// filters of opts, read from the total of a single page of one runtime group.
+// An error is returned when the API does not report the total. Several
+// cluster types are counted one after the other.
+func (c *Client) CountRuntimeGroups(ctx context.Context, opts ListOptions) (int, error) {
+	if len(opts.ClusterTypes) > 1 {
+		total := 0
+		for _, clusterType := range opts.ClusterTypes {
+			typeOpts := opts
+			typeOpts.ClusterTypes = []ClusterType{clusterType}
+
+			count, err := c.CountRuntimeGroups(ctx, typeOpts)
+			if err != nil {
+				return 0, err
+			}
+			total += count
+		}
+
+		return total, nil
+	}
+
+	opts.PageSize = 1
+	opts.PageNumber = 1
+
+	page, err := c.ListRuntimeGroups(ctx, opts)
+	if err != nil {
+		return 0, c.wrap("counting runtime groups", err)
+	}
+
+	if page.Meta.Page.Total == nil {
+		return 0, c.wrap("counting runtime groups", fmt.Errorf("the API did not report the total number of runtime groups"))
+	}
+
+	return *page.Meta.Page.Total, nil
+}
+
+// GetRuntimeGroupByName returns the runtime group with the given name, or
+// ErrNotFound when there is none. Names are unique within an organization.
+func (c *Client) GetRuntimeGroupByName(ctx context.Context, name string) (*CreateRuntimeGroupResponse, error) {
+	all, err := c.listAllRuntimeGroups(ctx, ListOptions{Name: name})
+	if err != nil {
+		return nil, c.wrap("listing runtime groups named "+strconv.Quote(name), err)
+	}
+
+	// Servers ignoring the filter return every runtime group.
+	for i := range all.Data {
+		if all.Data[i].Name == name {
+			return &all.Data[i], nil
+		}
+	}
+
+	return nil, c.wrap("finding runtime group named "+strconv.Quote(name), ErrNotFound)
+}
+
+// sortRuntimeGroups sorts groups by the given field, name when empty, breaking
+// ties by id.
+func sortRuntimeGroups(groups []CreateRuntimeGroupResponse, field string, descending bool) {
+	key := func(group CreateRuntimeGroupResponse) string {
+		switch field {
+		case "created_at":
+			return group.CreatedAt
+		case "updated_at":
+			return group.UpdatedAt
+		}
+
+		return group.Name
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		a, b := groups[i], groups[j]
+		if descending {
+			a, b = b, a
+		}
+
+		if key(a) != key(b) {
+			return key(a) < key(b)
+		}
+
+		return a.ID < b.ID
+	})
+}
+
+// lastPage reports whether no more pages are expected after page. Servers that
+// report neither the total nor the page size are paged until an empty page.
+func lastPage(page *ListRuntimeGroupsResponse, pageSize, fetched int) bool {
+	if len(page.Data) == 0 {
+		return true
+	}
+
+	if total := page.Meta.Page.Total; total != nil {
+		return fetched >= *total
+	}
+
+	if page.Meta.Page.Size > 0 {
+		pageSize = page.Meta.Page.Size
+	}
+
+	return pageSize > 0 && len(page.Data) < pageSize
+}
@@ -0,0 +1,522 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestListRuntimeGroups(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/runtime-groups" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+
+		if got := r.URL.Query().Get("page[size]"); got != "2" {
+			t.Errorf("expected page[size]=2, got %q", got)
+		}
+
+		fmt.Fprint(w, `{"meta":{"page":{"number":1,"size":2,"total":3}},"data":[{"id":"a","name":"one"},{"id":"b","name":"two"}]}`)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	resp, err := c.ListRuntimeGroups(context.Background(), ListOptions{PageSize: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(resp.Data) != 2 {
+		t.Fatalf("expected 2 runtime groups, got %d", len(resp.Data))
+	}
+
+	if resp.Meta.Page.Total == nil || *resp.Meta.Page.Total != 3 {
+		t.Fatalf("expected total 3, got %v", resp.Meta.Page.Total)
+	}
+}
+
+func TestListRuntimeGroupsWithoutTotal(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"meta":{"page":{"number":1,"size":10}},"data":[]}`)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	resp, err := c.ListRuntimeGroups(context.Background(), ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if resp.Meta.Page.Total != nil {
+		t.Fatalf("expected nil total, got %d", *resp.Meta.Page.Total)
+	}
+}
+
+func TestListAllRuntimeGroups(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		switch r.URL.Query().Get("page[number]") {
+		case "1":
+			fmt.Fprint(w, `{"meta":{"page":{"number":1,"size":2,"total":3}},"data":[{"id":"a"},{"id":"b"}]}`)
+		case "2":
+			fmt.Fprint(w, `{"meta":{"page":{"number":2,"size":2,"total":3}},"data":[{"id":"c"}]}`)
+		default:
+			t.Errorf("unexpected page %q", r.URL.Query().Get("page[number]"))
+		}
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	resp, err := c.ListAllRuntimeGroups(context.Background(), ListOptions{PageSize: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(resp.Data) != 3 || requests != 2 {
+		t.Fatalf("expected 3 runtime groups in 2 requests, got %d in %d", len(resp.Data), requests)
+	}
+
+	if resp.Meta.Page.Total == nil || *resp.Meta.Page.Total != 3 {
+		t.Fatalf("expected total 3, got %v", resp.Meta.Page.Total)
+	}
+}
+
+func TestCountRuntimeGroups(t *testing.T) {
+	tests := map[string]struct {
+		body    string
+		want    int
+		wantErr bool
+	}{
+		"total":         {body: `{"meta":{"page":{"number":1,"size":1,"total":42}},"data":[{"id":"a"}]}`, want: 42},
+		"no total":      {body: `{"meta":{"page":{"number":1,"size":1}},"data":[{"id":"a"}]}`, wantErr: true},
+		"no such group": {body: `{"meta":{"page":{"number":1,"size":1,"total":0}},"data":[]}`, want: 0},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				query := r.URL.Query()
+				if query.Get("page[size]") != "1" || query.Get("filter[name][eq]") != "test" {
+					t.Errorf("unexpected query: %s", r.URL.RawQuery)
+				}
+
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+
+			c, err := New(server.URL, testToken(t))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			got, err := c.CountRuntimeGroups(context.Background(), ListOptions{Name: "test", PageSize: 50})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %t, got %v", tt.wantErr, err)
+			}
+
+			if got != tt.want {
+				t.Fatalf("expected %d runtime groups, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestListAllRuntimeGroupsLimit(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		fmt.Fprint(w, `{"meta":{"page":{"number":1,"size":2,"total":5}},"data":[{"id":"a"},{"id":"b"}]}`)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	resp, err := c.ListAllRuntimeGroups(context.Background(), ListOptions{PageSize: 2, Limit: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(resp.Data) != 1 || requests != 1 {
+		t.Fatalf("expected 1 runtime group in 1 request, got %d in %d", len(resp.Data), requests)
+	}
+
+	requests = 0
+	resp, err = c.ListAllRuntimeGroups(context.Background(), ListOptions{PageSize: 2, Limit: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(resp.Data) != 2 || requests != 1 {
+		t.Fatalf("expected 2 runtime groups in 1 request, got %d in %d", len(resp.Data), requests)
+	}
+}
+
+func TestListOptionsFilters(t *testing.T) {
+	tests := map[string]struct {
+		opts ListOptions
+		want string
+	}{
+		"none": {
+			opts: ListOptions{},
+			want: "",
+		},
+		"single value": {
+			opts: ListOptions{ClusterTypes: []ClusterType{ClusterTypeHybrid}},
+			want: "filter[cluster_type]=CLUSTER_TYPE_HYBRID",
+		},
+		"labels": {
+			opts: ListOptions{
+				ClusterTypes: []ClusterType{ClusterTypeHybrid},
+				Labels:       []string{"env:prod", "team"},
+			},
+			want: "filter[cluster_type]=CLUSTER_TYPE_HYBRID&labels=env:prod,team",
+		},
+		"with pagination": {
+			opts: ListOptions{PageSize: 10, Labels: []string{"env:prod"}},
+			want: "labels=env:prod&page[size]=10",
+		},
+		"modified since": {
+			opts: ListOptions{ModifiedSince: time.Date(2023, 1, 2, 4, 5, 6, 0, time.FixedZone("CET", 3600))},
+			want: "filter[updated_at][gte]=2023-01-02T03:05:06Z",
+		},
+		"sort ascending": {
+			opts: ListOptions{SortBy: "name"},
+			want: "sort=name",
+		},
+		"sort descending": {
+			opts: ListOptions{SortBy: "created_at", SortOrder: SortDescending},
+			want: "sort=-created_at",
+		},
+		"include": {
+			opts: ListOptions{Include: []string{IncludeConfig, IncludeStatus}},
+			want: "include=config,status",
+		},
+		"fields": {
+			opts: ListOptions{Fields: []string{"name", "labels"}},
+			want: "fields[runtime_group]=name,labels",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := url.QueryUnescape(tt.opts.values().Encode())
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if got != tt.want {
+				t.Fatalf("expected query %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetRuntimeGroupInclude(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("include"))
+
+		if r.URL.Query().Get("include") == "" {
+			fmt.Fprint(w, `{"id":"rg-1","config":{"control_plane_endpoint":"https://cp.example.com"}}`)
+			return
+		}
+
+		fmt.Fprint(w, `{"id":"rg-1","config":{"control_plane_endpoint":"https://cp.example.com"},"status":{"state":"ready"}}`)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t), WithReadCache(true))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ctx := context.Background()
+	opts := GetOptions{Include: []string{IncludeConfig, IncludeStatus}}
+
+	// The cached runtime group lacks the status, so it is fetched again.
+	if _, err := c.GetRuntimeGroup(ctx, "rg-1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		group, err := c.GetRuntimeGroupWithOptions(ctx, "rg-1", opts)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if group.Status == nil || group.Status.State != "ready" || group.Config.ControlPlaneEndpoint != "https://cp.example.com" {
+			t.Fatalf("expected the expanded status and config, got %+v", group)
+		}
+	}
+
+	if want := []string{"", "config,status"}; !reflect.DeepEqual(queries, want) {
+		t.Fatalf("expected include parameters %q, got %q", want, queries)
+	}
+}
+
+func TestGetRuntimeGroupFields(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Query().Get("fields[runtime_group]"))
+
+		if r.URL.Query().Get("fields[runtime_group]") != "" {
+			fmt.Fprint(w, `{"id":"rg-1","name":"test","labels":{"env":"dev"}}`)
+			return
+		}
+
+		fmt.Fprint(w, `{"id":"rg-1","name":"test","labels":{"env":"dev"},"config":{"control_plane_endpoint":"https://cp.example.com"}}`)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t), WithReadCache(true))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ctx := context.Background()
+	group, err := c.GetRuntimeGroupWithOptions(ctx, "rg-1", GetOptions{Fields: []string{"name", "labels"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if group.Name != "test" || group.Labels["env"] != "dev" || group.Config.ControlPlaneEndpoint != "" {
+		t.Fatalf("expected the requested fields only, got %+v", group)
+	}
+
+	// The partial runtime group is not cached, the full one is fetched.
+	group, err = c.GetRuntimeGroup(ctx, "rg-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if group.Config.ControlPlaneEndpoint != "https://cp.example.com" {
+		t.Fatalf("expected the full runtime group, got %+v", group)
+	}
+
+	if want := []string{"name,labels", ""}; !reflect.DeepEqual(queries, want) {
+		t.Fatalf("expected fields parameters %q, got %q", want, queries)
+	}
+}
+
+func TestListRuntimeGroupsFilters(t *testing.T) {
+	var queried []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clusterType := r.URL.Query().Get("filter[cluster_type]")
+		queried = append(queried, clusterType)
+
+		// The server ignores the filter for the composite runtime groups.
+		switch ClusterType(clusterType) {
+		case ClusterTypeHybrid:
+			fmt.Fprint(w, `{"meta":{"page":{"number":1,"size":10,"total":1}},"data":[{"id":"rg-1","name":"hybrid"}]}`)
+		default:
+			fmt.Fprint(w, `{"meta":{"page":{"number":1,"size":10,"total":2}},"data":[{"id":"rg-1","name":"hybrid"},{"id":"rg-2","name":"composite"}]}`)
+		}
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ctx := context.Background()
+	opts := ListOptions{ClusterTypes: []ClusterType{ClusterTypeHybrid, ClusterTypeComposite}}
+
+	all, err := c.ListAllRuntimeGroups(ctx, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if want := []string{string(ClusterTypeHybrid), string(ClusterTypeComposite)}; !reflect.DeepEqual(queried, want) {
+		t.Fatalf("expected one query per cluster type %q, got %q", want, queried)
+	}
+
+	if len(all.Data) != 2 || all.Data[0].ID != "rg-2" || all.Data[1].ID != "rg-1" {
+		t.Fatalf("expected rg-2 and rg-1 once each, sorted by name, got %+v", all.Data)
+	}
+
+	if all.Meta.Page.Total == nil || *all.Meta.Page.Total != 3 {
+		t.Fatalf("expected the summed total 3, got %v", all.Meta.Page.Total)
+	}
+
+	count, err := c.CountRuntimeGroups(ctx, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if count != 3 {
+		t.Fatalf("expected a count of 3, got %d", count)
+	}
+
+	// A single page cannot span several queries.
+	if _, err := c.ListRuntimeGroups(ctx, opts); err == nil {
+		t.Fatal("expected an error listing a single page of several cluster types")
+	}
+}
+
+func TestListAllRuntimeGroupsStableOrder(t *testing.T) {
+	// The API returns the same runtime groups in a different order on every call.
+	bodies := []string{
+		`{"meta":{"page":{"number":1,"size":10,"total":3}},"data":[{"id":"c","name":"beta"},{"id":"a","name":"alpha"},{"id":"b","name":"beta"}]}`,
+		`{"meta":{"page":{"number":1,"size":10,"total":3}},"data":[{"id":"b","name":"beta"},{"id":"c","name":"beta"},{"id":"a","name":"alpha"}]}`,
+	}
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, bodies[requests%len(bodies)])
+		requests++
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := []string{"a", "b", "c"}
+	for i := range bodies {
+		resp, err := c.ListAllRuntimeGroups(context.Background(), ListOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		var got []string
+		for _, group := range resp.Data {
+			got = append(got, group.ID)
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("call %d: expected order %v, got %v", i, want, got)
+		}
+	}
+}
+
+func TestGetRuntimeGroupByName(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("filter[name][eq]"); got == "missing" {
+			fmt.Fprint(w, `{"meta":{"page":{"number":1,"size":10,"total":0}},"data":[]}`)
+			return
+		}
+
+		// Behave like a server ignoring the filter.
+		fmt.Fprint(w, `{"meta":{"page":{"number":1,"size":10,"total":2}},"data":[{"id":"a","name":"alpha"},{"id":"b","name":"beta"}]}`)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	group, err := c.GetRuntimeGroupByName(context.Background(), "beta")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if group.ID != "b" {
+		t.Fatalf("expected runtime group b, got %s", group.ID)
+	}
+
+	for _, name := range []string{"missing", "gamma"} {
+		if _, err := c.GetRuntimeGroupByName(context.Background(), name); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("expected ErrNotFound for %s, got %v", name, err)
+		}
+	}
+}
+
+func TestListAllRuntimeGroupsSort(t *testing.T) {
+	// The server ignores the sort parameter.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("sort"); got != "-created_at" {
+			t.Errorf("expected sort -created_at, got %q", got)
+		}
+
+		fmt.Fprint(w, `{"meta":{"page":{"number":1,"size":10,"total":3}},"data":[`+
+			`{"id":"a","name":"alpha","created_at":"2023-01-02T00:00:00Z"},`+
+			`{"id":"b","name":"beta","created_at":"2023-01-03T00:00:00Z"},`+
+			`{"id":"c","name":"gamma","created_at":"2023-01-01T00:00:00Z"}]}`)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	resp, err := c.ListAllRuntimeGroups(context.Background(), ListOptions{SortBy: "created_at", SortOrder: SortDescending})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var got []string
+	for _, group := range resp.Data {
+		got = append(got, group.ID)
+	}
+
+	if want := []string{"b", "a", "c"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected order %v, got %v", want, got)
+	}
+
+	if _, err := c.ListRuntimeGroups(context.Background(), ListOptions{SortBy: "labels"}); err == nil {
+		t.Fatal("expected an error for an unknown sort field")
+	}
+}
+
+func TestListRuntimeGroupsModifiedSinceUnsupported(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		filter := r.URL.Query().Get("filter[updated_at][gte]")
+		requests = append(requests, filter)
+
+		if filter != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"status":400,"title":"Bad Request","detail":"unknown filter"}`)
+			return
+		}
+
+		fmt.Fprint(w, `{"meta":{"page":{"number":1,"size":10,"total":1}},"data":[{"id":"a","name":"alpha"}]}`)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	resp, err := c.ListRuntimeGroups(context.Background(), ListOptions{ModifiedSince: time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(resp.Data) != 1 {
+		t.Fatalf("expected every runtime group, got %+v", resp.Data)
+	}
+
+	if want := []string{"2023-01-02T00:00:00Z", ""}; !reflect.DeepEqual(requests, want) {
+		t.Fatalf("expected filters %q, got %q", want, requests)
+	}
+}
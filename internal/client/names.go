@@ -0,0 +1,54 @@
+package client
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxNameLength is the maximum length of a runtime group name, in characters.
+const MaxNameLength = 100
+
+// uniqueNameTimeFormat is the UTC timestamp of the names built by UniqueName.
+const uniqueNameTimeFormat = "20060102150405"
+
+// uniqueSuffixLength is the length of the suffix appended by UniqueName: the
+// timestamp followed by 8 random hex digits.
+const uniqueSuffixLength = len(uniqueNameTimeFormat) + 8
+
+// ValidateName checks that name is a runtime group name the API accepts.
+func ValidateName(name string) error {
+	if name == "" {
+		return fmt.Errorf("name must not be empty")
+	}
+
+	if length := utf8.RuneCountInString(name); length > MaxNameLength {
+		return fmt.Errorf("name must be at most %d characters, got %d", MaxNameLength, length)
+	}
+
+	return nil
+}
+
+// UniqueName appends a UTC timestamp and a random suffix to prefix, so that
+// names generated from the same prefix, e.g. by parallel CI runs, do not
+// collide. The prefix is truncated so that the name fits in MaxNameLength.
+func UniqueName(prefix string) string {
+	if maxPrefix := MaxNameLength - uniqueSuffixLength; utf8.RuneCountInString(prefix) > maxPrefix {
+		prefix = string([]rune(prefix)[:maxPrefix])
+	}
+
+	now := time.Now().UTC()
+
+	var random string
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err == nil {
+		random = hex.EncodeToString(suffix)
+	} else {
+		// Fall back to the nanoseconds, unique enough within a process.
+		random = fmt.Sprintf("%08x", now.Nanosecond())
+	}
+
+	return prefix + now.Format(uniqueNameTimeFormat) + random
+}
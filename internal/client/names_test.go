@@ -0,0 +1,63 @@
+package client
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestUniqueName(t *testing.T) {
+	tests := map[string]struct {
+		prefix     string
+		wantPrefix string
+	}{
+		"empty prefix": {},
+		"short prefix": {prefix: "ci-", wantPrefix: "ci-"},
+		"long prefix": {
+			prefix:     strings.Repeat("é", MaxNameLength),
+			wantPrefix: strings.Repeat("é", MaxNameLength-uniqueSuffixLength),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			seen := make(map[string]bool)
+			for i := 0; i < 1000; i++ {
+				got := UniqueName(tt.prefix)
+
+				if err := ValidateName(got); err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+
+				if !strings.HasPrefix(got, tt.wantPrefix) || utf8.RuneCountInString(got) != utf8.RuneCountInString(tt.wantPrefix)+uniqueSuffixLength {
+					t.Fatalf("expected %q followed by a %d characters suffix, got %q", tt.wantPrefix, uniqueSuffixLength, got)
+				}
+
+				if seen[got] {
+					t.Fatalf("expected unique names, got %q twice", got)
+				}
+				seen[got] = true
+			}
+		})
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	tests := map[string]struct {
+		name    string
+		wantErr bool
+	}{
+		"valid":      {name: "default"},
+		"max length": {name: strings.Repeat("a", MaxNameLength)},
+		"empty":      {wantErr: true},
+		"too long":   {name: strings.Repeat("a", MaxNameLength+1), wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := ValidateName(tt.name); (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %t, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,573 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Option configures optional behaviour of the Client.
+type Option func(*Client) error
+
+// WithBasePath mounts every endpoint under prefix, e.g. "/konnect/v2" turns
+// "/runtime-groups" into "host/konnect/v2/runtime-groups".
+func WithBasePath(prefix string) Option {
+	return func(c *Client) error {
+		parsed, err := url.Parse(prefix)
+		if err != nil {
+			return fmt.Errorf("parsing base path: %w", err)
+		}
+
+		if parsed.Scheme != "" || parsed.Host != "" || parsed.RawQuery != "" || parsed.Fragment != "" || parsed.ForceQuery {
+			return fmt.Errorf("base path %q must be a plain URL path", prefix)
+		}
+
+		c.basePath = parsed.Path
+
+		return nil
+	}
+}
+
+// WithEndpointOverrides replaces the base URL of the operations named by the
+// keys of overrides, see Operations, e.g. to point them at distinct mock
+// servers. The base path and the endpoint are still joined to the override.
+func WithEndpointOverrides(overrides map[string]string) Option {
+	return func(c *Client) error {
+		for operation, override := range overrides {
+			if !validOperation(operation) {
+				return fmt.Errorf("unknown operation %q, expected one of %s", operation, strings.Join(Operations(), ", "))
+			}
+
+			if err := ValidateEndpoint(override); err != nil {
+				return fmt.Errorf("invalid endpoint override of %s: %w", operation, err)
+			}
+		}
+
+		c.endpointOverrides = make(map[string]string, len(overrides))
+		for operation, override := range overrides {
+			c.endpointOverrides[operation] = override
+		}
+
+		return nil
+	}
+}
+
+// ValidateEndpoint checks that endpoint is an absolute http or https URL.
+func ValidateEndpoint(endpoint string) error {
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("%q must be an absolute http or https URL", endpoint)
+	}
+
+	return nil
+}
+
+// validOperation reports whether operation is one of Operations.
+func validOperation(operation string) bool {
+	for _, known := range Operations() {
+		if operation == known {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WithTokenSource makes the Client source its bearer token from source
+// whenever the cached token is missing or no longer valid.
+func WithTokenSource(source TokenSource) Option {
+	return func(c *Client) error {
+		if source == nil {
+			return fmt.Errorf("token source must not be nil")
+		}
+
+		c.tokenSource = source
+
+		return nil
+	}
+}
+
+// WithDefaultLabels sets labels merged into the labels of every created
+// runtime group. Labels of the request win on key conflicts. Values may
+// reference the runtime group as $name and $cluster_type, e.g. "$name-owner",
+// expanded when the labels are merged, $cluster_type to nothing for requests
+// without a cluster type; $$ stands for a literal $. Any other token is an
+// error.
+func WithDefaultLabels(labels map[string]string) Option {
+	return func(c *Client) error {
+		if _, err := expandLabelTemplates(labels, labelVars{}); err != nil {
+			return fmt.Errorf("default labels: %w", err)
+		}
+
+		c.defaultLabels = copyLabels(labels)
+
+		return nil
+	}
+}
+
+// WithDefaultClusterType sets the cluster type of the runtime groups created
+// without one by the provider. The Client does not apply it to the requests
+// itself, see DefaultClusterType. Empty unsets it.
+func WithDefaultClusterType(clusterType ClusterType) Option {
+	return func(c *Client) error {
+		if clusterType != "" && !clusterType.Valid() {
+			return fmt.Errorf("default cluster type %q is not one of %v", clusterType, clusterTypes)
+		}
+
+		c.defaultClusterType = clusterType
+
+		return nil
+	}
+}
+
+// WithSkipTokenValidation bypasses the validation of the token in New, e.g. to
+// plan without credentials. Requests still fail without a token.
+func WithSkipTokenValidation(skip bool) Option {
+	return func(c *Client) error {
+		c.skipTokenValidation = skip
+
+		return nil
+	}
+}
+
+// WithAuthScheme sets the scheme of the Authorization header, "Bearer" by
+// default. It cannot be combined with WithRawAuthHeader.
+func WithAuthScheme(scheme string) Option {
+	return func(c *Client) error {
+		if scheme == "" || strings.ContainsAny(scheme, " \t\r\n") {
+			return fmt.Errorf("auth scheme %q must be a single non-empty token", scheme)
+		}
+
+		c.authScheme = scheme
+
+		return nil
+	}
+}
+
+// WithRawAuthHeader sets the whole value of the Authorization header, e.g. for
+// proxies expecting a custom header. The token is not sent when it is set. It
+// cannot be combined with WithAuthScheme.
+func WithRawAuthHeader(value string) Option {
+	return func(c *Client) error {
+		if value == "" || strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("raw auth header must be a non-empty single line")
+		}
+
+		c.rawAuthHeader = value
+
+		return nil
+	}
+}
+
+// WithRetry retries transient failures up to maxRetries times, waiting backoff
+// before the first retry and doubling the delay on every attempt, less some
+// jitter. WithBackoff changes the pacing.
+func WithRetry(maxRetries int, backoff time.Duration) Option {
+	return func(c *Client) error {
+		if maxRetries < 0 || backoff < 0 {
+			return fmt.Errorf("max retries and backoff must not be negative")
+		}
+
+		c.maxRetries = maxRetries
+		c.retryBackoff = backoff
+
+		return nil
+	}
+}
+
+// WithBackoff paces the retries of WithRetry with strategy, by default an
+// ExponentialBackoff with jitter.
+func WithBackoff(strategy BackoffStrategy) Option {
+	return func(c *Client) error {
+		if strategy == nil {
+			return fmt.Errorf("backoff strategy must not be nil")
+		}
+
+		c.backoff = strategy
+
+		return nil
+	}
+}
+
+// WithClock replaces the clock used for the retry backoff and the token expiry
+// checks, the real clock by default.
+func WithClock(clk clock) Option {
+	return func(c *Client) error {
+		if clk == nil {
+			return fmt.Errorf("clock must not be nil")
+		}
+
+		c.clock = clk
+
+		return nil
+	}
+}
+
+// RequestInterceptor mutates a request before it is sent, e.g. to sign it or
+// add dynamic headers. An error aborts the request.
+type RequestInterceptor func(ctx context.Context, req *http.Request) error
+
+// WithRequestInterceptor registers intercept to run on every request, after the
+// Content-Type and Authorization headers are set, so it sees and may override
+// them. Interceptors run in the order they are registered. Retries of transient
+// failures resend the intercepted request, a retry with a refreshed token runs
+// the interceptors again.
+func WithRequestInterceptor(intercept RequestInterceptor) Option {
+	return func(c *Client) error {
+		if intercept == nil {
+			return fmt.Errorf("request interceptor must not be nil")
+		}
+
+		c.interceptors = append(c.interceptors, intercept)
+
+		return nil
+	}
+}
+
+// RoundTripperWrapper wraps the round tripper performing the requests, e.g. to
+// layer caching or tracing middleware.
+type RoundTripperWrapper func(http.RoundTripper) http.RoundTripper
+
+// WithRoundTripperWrapper wraps the transport of the client with wrap. Wrappers
+// compose in the order they are registered: the first one sees the requests
+// first and the responses last. They run below the retries and the
+// interceptors, once per attempt.
+func WithRoundTripperWrapper(wrap RoundTripperWrapper) Option {
+	return func(c *Client) error {
+		if wrap == nil {
+			return fmt.Errorf("round tripper wrapper must not be nil")
+		}
+
+		c.roundTripperWrappers = append(c.roundTripperWrappers, wrap)
+
+		return nil
+	}
+}
+
+// WithMetricsRecorder records the method, status code, duration and error of
+// every request with recorder. Nothing is recorded without it.
+func WithMetricsRecorder(recorder MetricsRecorder) Option {
+	return func(c *Client) error {
+		if recorder == nil {
+			return fmt.Errorf("metrics recorder must not be nil")
+		}
+
+		c.metrics = recorder
+
+		return nil
+	}
+}
+
+// WithTracer starts a span per request with tracer, recording the method,
+// host, status code and retry attempt of the request. No span is started
+// without it. oteltrace.WithTracerProvider sets an OpenTelemetry tracer.
+func WithTracer(tracer Tracer) Option {
+	return func(c *Client) error {
+		if tracer == nil {
+			return fmt.Errorf("tracer must not be nil")
+		}
+
+		c.tracer = tracer
+
+		return nil
+	}
+}
+
+// WithMaxConcurrency caps the number of requests in flight at once across
+// every caller of the client, whatever the parallelism of Terraform. Requests
+// beyond it wait for a slot or for their context to be done. Zero, the default,
+// is unlimited.
+func WithMaxConcurrency(n int) Option {
+	return func(c *Client) error {
+		if n < 0 {
+			return fmt.Errorf("max concurrency must not be negative")
+		}
+
+		c.slots = nil
+		if n > 0 {
+			c.slots = make(chan struct{}, n)
+		}
+
+		return nil
+	}
+}
+
+// WithMethodOverride sends the PATCH, PUT and DELETE requests as POST requests
+// with their real method in the X-HTTP-Method-Override header, for gateways
+// blocking these methods. The API must honour the header.
+func WithMethodOverride(enabled bool) Option {
+	return func(c *Client) error {
+		c.methodOverride = enabled
+
+		return nil
+	}
+}
+
+// WithMergePatch sends updates as JSON Merge Patch documents, with the
+// application/merge-patch+json Content-Type, for the APIs supporting them. Only
+// the labels changed from UpdateRuntimeGroupRequest.PriorLabels are sent, and
+// the removed ones as null, so labels set outside of the update are kept.
+func WithMergePatch(enabled bool) Option {
+	return func(c *Client) error {
+		c.mergePatch = enabled
+
+		return nil
+	}
+}
+
+// WithMaxBodyLogBytes truncates the request and response bodies logged at
+// trace level to n bytes, 4KB by default. Zero leaves the bodies out of the
+// logs. Secrets are redacted before truncating. The bodies are only read when
+// TF_LOG_PROVIDER or TF_LOG enables trace logs.
+func WithMaxBodyLogBytes(n int) Option {
+	return func(c *Client) error {
+		if n < 0 {
+			return fmt.Errorf("max body log bytes must not be negative")
+		}
+
+		c.maxBodyLogBytes = n
+
+		return nil
+	}
+}
+
+// ResponseValidator checks a decoded response, e.g. to enforce a policy. The
+// response is a *CreateRuntimeGroupResponse, *ListRuntimeGroupsResponse or
+// *RuntimeGroupStatus. An error aborts the operation.
+type ResponseValidator func(response any) error
+
+// WithResponseValidator registers validate to run on every successfully decoded
+// response, in the order the validators are registered.
+func WithResponseValidator(validate ResponseValidator) Option {
+	return func(c *Client) error {
+		if validate == nil {
+			return fmt.Errorf("response validator must not be nil")
+		}
+
+		c.responseValidators = append(c.responseValidators, validate)
+
+		return nil
+	}
+}
+
+// WithContextTimeout sets the timeout of the requests whose context has no
+// deadline, 60s by default. Zero disables it. The deadline of a context set by
+// the caller is always respected.
+func WithContextTimeout(timeout time.Duration) Option {
+	return func(c *Client) error {
+		if timeout < 0 {
+			return fmt.Errorf("context timeout must not be negative")
+		}
+
+		c.fallbackTimeout = timeout
+
+		return nil
+	}
+}
+
+// WithRequestTimeout bounds every request by timeout, including the requests
+// whose context has a deadline: the earlier of the two wins. Zero disables it,
+// the default.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *Client) error {
+		if timeout < 0 {
+			return fmt.Errorf("request timeout must not be negative")
+		}
+
+		c.requestTimeout = timeout
+
+		return nil
+	}
+}
+
+// WithBaseContext derives the context of every request from ctx as well as
+// from the context passed to the call, e.g. the lifecycle context of a server:
+// once ctx is done, requests in flight and future ones abort. Its deadline
+// applies to every request, and its values are visible to the interceptors
+// when the call context does not set them.
+func WithBaseContext(ctx context.Context) Option {
+	return func(c *Client) error {
+		if ctx == nil {
+			return fmt.Errorf("base context must not be nil")
+		}
+
+		c.baseCtx = ctx
+
+		return nil
+	}
+}
+
+// WithForceHTTP1 forces HTTP/1.1, e.g. for proxies mishandling HTTP/2. HTTP/2
+// is attempted by default, as with the standard library.
+func WithForceHTTP1(force bool) Option {
+	return func(c *Client) error {
+		c.forceHTTP1 = force
+
+		return nil
+	}
+}
+
+// WithDisableCompression stops asking for gzip compressed responses, e.g. to
+// read the raw traffic when debugging. Responses are compressed by default, and
+// transparently decompressed, as with the standard library.
+func WithDisableCompression(disable bool) Option {
+	return func(c *Client) error {
+		c.disableCompression = disable
+
+		return nil
+	}
+}
+
+// WithDialContext opens the connections with dial instead of the default
+// dialer, e.g. to resolve the API host with split-horizon DNS or to redirect it
+// to a local server in tests. The address passed to dial is the host and port
+// of the base URL, or of the proxy when one is used.
+func WithDialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) Option {
+	return func(c *Client) error {
+		if dial == nil {
+			return fmt.Errorf("dial context function must not be nil")
+		}
+
+		c.dialContext = dial
+
+		return nil
+	}
+}
+
+// WithKeepAlive enables the reuse of connections across requests, enabled by
+// default as with the standard library.
+func WithKeepAlive(keepAlive bool) Option {
+	return func(c *Client) error {
+		c.disableKeepAlives = !keepAlive
+
+		return nil
+	}
+}
+
+// WithPerResourceSerialization serializes the mutating operations on the same
+// runtime group, e.g. an update racing a deletion, as the API may not handle
+// them concurrently. Operations on different runtime groups, and reads, still
+// run concurrently. Disabled by default.
+func WithPerResourceSerialization(enabled bool) Option {
+	return func(c *Client) error {
+		c.locks = nil
+		if enabled {
+			c.locks = &groupLocks{}
+		}
+
+		return nil
+	}
+}
+
+// WithReadCache caches the runtime groups read by id for the lifetime of the
+// Client, so repeated reads of the same runtime group skip the API. Updating,
+// deleting or rotating the credentials of a runtime group drops it from the
+// cache.
+func WithReadCache(enabled bool) Option {
+	return func(c *Client) error {
+		c.cache = nil
+		if enabled {
+			c.cache = &readCache{}
+		}
+
+		return nil
+	}
+}
+
+// WithETagCache caches the runtime groups read by id with the ETag returned by
+// the API, and sends it in the If-None-Match header of the following reads. The
+// cached runtime group is returned when the API answers 304 Not Modified,
+// saving the transfer of unchanged runtime groups. Unlike WithReadCache, every
+// read is validated by the API.
+func WithETagCache(enabled bool) Option {
+	return func(c *Client) error {
+		c.etags = nil
+		if enabled {
+			c.etags = &etagCache{}
+		}
+
+		return nil
+	}
+}
+
+// WithReadCacheTTL enables the cache of WithReadCache with entries expiring
+// after ttl, e.g. for long-lived clients. Zero disables the cache.
+func WithReadCacheTTL(ttl time.Duration) Option {
+	return func(c *Client) error {
+		if ttl < 0 {
+			return fmt.Errorf("read cache TTL must not be negative")
+		}
+
+		c.cache = nil
+		if ttl > 0 {
+			c.cache = &readCache{ttl: ttl}
+		}
+
+		return nil
+	}
+}
+
+// RequestEncoder serializes the body of a request, a CreateRuntimeGroupRequest
+// or an UpdateRuntimeGroupRequest, returning it with its Content-Type.
+type RequestEncoder func(body any) ([]byte, string, error)
+
+// WithRequestEncoder serializes the request bodies with encoder rather than as
+// JSON, e.g. for API variants naming the fields differently. Updates sent as
+// JSON Merge Patch documents, see WithMergePatch, are not affected.
+func WithRequestEncoder(encoder RequestEncoder) Option {
+	return func(c *Client) error {
+		if encoder == nil {
+			return fmt.Errorf("request encoder must not be nil")
+		}
+
+		c.encoder = encoder
+
+		return nil
+	}
+}
+
+// WithIdempotencyKeyFunc computes the Idempotency-Key header of the requests
+// creating runtime groups from their body, e.g. a content hash deduplicating
+// identical creates across runs. The default is a random UUID per request.
+func WithIdempotencyKeyFunc(fn func(CreateRuntimeGroupRequest) string) Option {
+	return func(c *Client) error {
+		if fn == nil {
+			return fmt.Errorf("idempotency key function must not be nil")
+		}
+
+		c.idempotencyKey = fn
+
+		return nil
+	}
+}
+
+// WithPreferMinimal sends "Prefer: return=minimal" with the requests creating
+// or updating runtime groups to reduce the size of the responses. Runtime
+// groups missing from the responses are fetched with GetRuntimeGroup.
+func WithPreferMinimal(preferMinimal bool) Option {
+	return func(c *Client) error {
+		c.preferMinimal = preferMinimal
+
+		return nil
+	}
+}
+
+// WithAcceptAny2xx treats every 2xx status code as a success, rather than only
+// 200, 201 and 204, for APIs creating runtime groups asynchronously. A create
+// answered with 202 Accepted and a Location header polls that location until
+// it stops answering 202, see CreateRuntimeGroup.
+func WithAcceptAny2xx(enabled bool) Option {
+	return func(c *Client) error {
+		c.acceptAny2xx = enabled
+
+		return nil
+	}
+}
@@ -0,0 +1,967 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithBasePath(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/konnect/v2/runtime-groups" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+
+		fmt.Fprint(w, `{"meta":{"page":{"number":1,"size":10,"total":0}},"data":[]}`)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t), WithBasePath("/konnect/v2"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	endpoint, err := c.endpoint(OperationListRuntimeGroups, runtimeGroupEndpoint)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if want := server.URL + "/konnect/v2/runtime-groups"; endpoint != want {
+		t.Fatalf("expected %s, got %s", want, endpoint)
+	}
+
+	if _, err := c.ListRuntimeGroups(context.Background(), ListOptions{}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestWithBasePathInvalid(t *testing.T) {
+	for _, prefix := range []string{"https://example.com/v2", "/v2?page=1", "/v2#top", "%zz"} {
+		if _, err := New("https://example.com", testToken(t), WithBasePath(prefix)); err == nil {
+			t.Errorf("expected an error for base path %q", prefix)
+		}
+	}
+}
+
+func TestAuthorizationHeader(t *testing.T) {
+	token := testToken(t)
+
+	tests := map[string]struct {
+		opts []Option
+		want string
+	}{
+		"default": {
+			want: "Bearer " + token,
+		},
+		"auth scheme": {
+			opts: []Option{WithAuthScheme("Token")},
+			want: "Token " + token,
+		},
+		"raw auth header": {
+			opts: []Option{WithRawAuthHeader("Custom secret")},
+			want: "Custom secret",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("Authorization"); got != test.want {
+					t.Errorf("expected Authorization %q, got %q", test.want, got)
+				}
+
+				fmt.Fprint(w, `{"id":"rg-1"}`)
+			}))
+			defer server.Close()
+
+			c, err := New(server.URL, token, test.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if _, err := c.GetRuntimeGroup(context.Background(), "rg-1"); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestAuthOptionsMutuallyExclusive(t *testing.T) {
+	if _, err := New("https://example.com", testToken(t), WithAuthScheme("Token"), WithRawAuthHeader("Custom secret")); err == nil {
+		t.Fatal("expected an error when both auth options are set")
+	}
+
+	if _, err := New("https://example.com", testToken(t), WithAuthScheme("Bad Scheme")); err == nil {
+		t.Fatal("expected an error for an auth scheme with spaces")
+	}
+}
+
+func TestWithEndpointOverrides(t *testing.T) {
+	base := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to the base URL: %s %s", r.Method, r.URL.Path)
+	}))
+	defer base.Close()
+
+	mock := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/runtime-groups" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":"rg-1","name":"test"}`)
+	}))
+	defer mock.Close()
+
+	c, err := New(base.URL, testToken(t), WithEndpointOverrides(map[string]string{
+		OperationCreateRuntimeGroup: mock.URL,
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := c.CreateRuntimeGroup(CreateRuntimeGroupRequest{Name: "test"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Operations without an override fall back to the base URL.
+	endpoint, err := c.endpoint(OperationGetRuntimeGroup, runtimeGroupEndpoint)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if want := base.URL + "/runtime-groups"; endpoint != want {
+		t.Fatalf("expected %s, got %s", want, endpoint)
+	}
+}
+
+func TestWithEndpointOverridesInvalid(t *testing.T) {
+	tests := map[string]map[string]string{
+		"unknown operation":  {"delete_everything": "https://example.com"},
+		"relative URL":       {OperationCreateRuntimeGroup: "/runtime-groups"},
+		"unsupported scheme": {OperationCreateRuntimeGroup: "ftp://example.com"},
+	}
+
+	for name, overrides := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := New("https://example.com", testToken(t), WithEndpointOverrides(overrides)); err == nil {
+				t.Fatal("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestWithRequestInterceptor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("X-Signature"); got != "signed" {
+			t.Errorf("unexpected X-Signature header: %q", got)
+		}
+
+		if got := r.Header.Get("X-Order"); got != "first,second" {
+			t.Errorf("expected interceptors to run in order, got %q", got)
+		}
+
+		fmt.Fprint(w, `{"id":"rg-1"}`)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t),
+		WithRequestInterceptor(func(ctx context.Context, req *http.Request) error {
+			// The built-in headers are already set.
+			if !strings.HasPrefix(req.Header.Get("Authorization"), "Bearer ") {
+				return fmt.Errorf("missing Authorization header")
+			}
+
+			req.Header.Set("X-Signature", "signed")
+			req.Header.Set("X-Order", "first")
+
+			return nil
+		}),
+		WithRequestInterceptor(func(ctx context.Context, req *http.Request) error {
+			req.Header.Set("X-Order", req.Header.Get("X-Order")+",second")
+
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := c.GetRuntimeGroup(context.Background(), "rg-1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestWithRequestInterceptorError(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	}))
+	defer server.Close()
+
+	errSigning := errors.New("signing failed")
+
+	c, err := New(server.URL, testToken(t), WithRequestInterceptor(func(ctx context.Context, req *http.Request) error {
+		return errSigning
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := c.GetRuntimeGroup(context.Background(), "rg-1"); !errors.Is(err, errSigning) {
+		t.Fatalf("expected the interceptor error, got %v", err)
+	}
+
+	if requests != 0 {
+		t.Fatalf("expected the request to be aborted, got %d requests", requests)
+	}
+}
+
+func TestWithContextTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"rg-1"}`)
+	}))
+	defer server.Close()
+
+	callerDeadline := time.Now().Add(time.Hour)
+
+	tests := map[string]struct {
+		ctx  func() (context.Context, context.CancelFunc)
+		opts []Option
+		// want returns the expected deadline, the zero time for none.
+		want func(sent time.Time) time.Time
+	}{
+		"default fallback": {
+			ctx: func() (context.Context, context.CancelFunc) { return context.Background(), func() {} },
+			want: func(sent time.Time) time.Time {
+				return sent.Add(defaultFallbackTimeout)
+			},
+		},
+		"configured fallback": {
+			ctx:  func() (context.Context, context.CancelFunc) { return context.Background(), func() {} },
+			opts: []Option{WithContextTimeout(5 * time.Second)},
+			want: func(sent time.Time) time.Time {
+				return sent.Add(5 * time.Second)
+			},
+		},
+		"caller deadline": {
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithDeadline(context.Background(), callerDeadline)
+			},
+			want: func(time.Time) time.Time {
+				return callerDeadline
+			},
+		},
+		"request timeout shortens caller deadline": {
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithDeadline(context.Background(), callerDeadline)
+			},
+			opts: []Option{WithRequestTimeout(10 * time.Second)},
+			want: func(sent time.Time) time.Time {
+				return sent.Add(10 * time.Second)
+			},
+		},
+		"request timeout shortens fallback": {
+			ctx:  func() (context.Context, context.CancelFunc) { return context.Background(), func() {} },
+			opts: []Option{WithRequestTimeout(10 * time.Second)},
+			want: func(sent time.Time) time.Time {
+				return sent.Add(10 * time.Second)
+			},
+		},
+		"earlier caller deadline wins": {
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithDeadline(context.Background(), callerDeadline)
+			},
+			opts: []Option{WithRequestTimeout(2 * time.Hour)},
+			want: func(time.Time) time.Time {
+				return callerDeadline
+			},
+		},
+		"disabled": {
+			ctx:  func() (context.Context, context.CancelFunc) { return context.Background(), func() {} },
+			opts: []Option{WithContextTimeout(0)},
+			want: func(time.Time) time.Time {
+				return time.Time{}
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var deadline time.Time
+			var sent time.Time
+			opts := append(tt.opts, WithRequestInterceptor(func(ctx context.Context, req *http.Request) error {
+				sent = time.Now()
+				deadline, _ = ctx.Deadline()
+
+				return nil
+			}))
+
+			c, err := New(server.URL, testToken(t), opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			if _, err := c.GetRuntimeGroup(ctx, "rg-1"); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			want := tt.want(sent)
+			if diff := deadline.Sub(want); diff < -time.Second || diff > time.Second || deadline.IsZero() != want.IsZero() {
+				t.Fatalf("expected deadline %s, got %s", want, deadline)
+			}
+		})
+	}
+}
+
+func TestTransportOptions(t *testing.T) {
+	tests := map[string]struct {
+		opts                  []Option
+		wantForceAttemptHTTP2 bool
+		wantTLSNextProto      bool
+		wantDisableKeepAlives bool
+	}{
+		"defaults": {
+			wantForceAttemptHTTP2: true,
+		},
+		"force HTTP/1.1": {
+			opts:             []Option{WithForceHTTP1(true)},
+			wantTLSNextProto: true,
+		},
+		"no keep-alive": {
+			opts:                  []Option{WithKeepAlive(false)},
+			wantForceAttemptHTTP2: true,
+			wantDisableKeepAlives: true,
+		},
+		"explicit defaults": {
+			opts:                  []Option{WithForceHTTP1(false), WithKeepAlive(true)},
+			wantForceAttemptHTTP2: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := New("https://example.com", testToken(t), tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			transport, ok := c.httpClient.Transport.(*http.Transport)
+			if !ok {
+				t.Fatalf("expected an *http.Transport, got %T", c.httpClient.Transport)
+			}
+
+			if transport.ForceAttemptHTTP2 != tt.wantForceAttemptHTTP2 {
+				t.Errorf("expected ForceAttemptHTTP2 %t, got %t", tt.wantForceAttemptHTTP2, transport.ForceAttemptHTTP2)
+			}
+
+			if got := transport.TLSNextProto != nil; got != tt.wantTLSNextProto {
+				t.Errorf("expected TLSNextProto set %t, got %t", tt.wantTLSNextProto, got)
+			}
+
+			if transport.DisableKeepAlives != tt.wantDisableKeepAlives {
+				t.Errorf("expected DisableKeepAlives %t, got %t", tt.wantDisableKeepAlives, transport.DisableKeepAlives)
+			}
+		})
+	}
+}
+
+func TestWithForceHTTP1(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor != 1 {
+			t.Errorf("expected HTTP/1.x, got %s", r.Proto)
+		}
+
+		fmt.Fprint(w, `{"id":"rg-1"}`)
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t), WithForceHTTP1(true))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Trust the certificate of the test server.
+	c.httpClient.Transport.(*http.Transport).TLSClientConfig = server.Client().Transport.(*http.Transport).TLSClientConfig
+
+	if _, err := c.GetRuntimeGroup(context.Background(), "rg-1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestWithDialContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Host != "konnect.invalid" {
+			t.Errorf("expected host konnect.invalid, got %q", r.Host)
+		}
+
+		fmt.Fprint(w, `{"id":"rg-1"}`)
+	}))
+	defer server.Close()
+
+	var dialed []string
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialed = append(dialed, addr)
+
+		var d net.Dialer
+
+		return d.DialContext(ctx, network, server.Listener.Addr().String())
+	}
+
+	c, err := New("http://konnect.invalid", testToken(t), WithDialContext(dial))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := c.GetRuntimeGroup(context.Background(), "rg-1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(dialed) != 1 || dialed[0] != "konnect.invalid:80" {
+		t.Errorf("expected a dial of konnect.invalid:80, got %v", dialed)
+	}
+
+	if _, err := New("http://konnect.invalid", testToken(t), WithDialContext(nil)); err == nil {
+		t.Error("expected an error for a nil dial context function")
+	}
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestWithDisableCompression(t *testing.T) {
+	tests := map[string]struct {
+		disable            bool
+		wantAcceptEncoding string
+	}{
+		"enabled":  {disable: false, wantAcceptEncoding: "gzip"},
+		"disabled": {disable: true, wantAcceptEncoding: ""},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("Accept-Encoding"); got != tt.wantAcceptEncoding {
+					t.Errorf("expected Accept-Encoding %q, got %q", tt.wantAcceptEncoding, got)
+				}
+
+				fmt.Fprint(w, `{"id":"rg-1"}`)
+			}))
+			defer server.Close()
+
+			c, err := New(server.URL, testToken(t), WithDisableCompression(tt.disable))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if got := c.httpClient.Transport.(*http.Transport).DisableCompression; got != tt.disable {
+				t.Fatalf("expected DisableCompression %t, got %t", tt.disable, got)
+			}
+
+			if _, err := c.GetRuntimeGroup(context.Background(), "rg-1"); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestWithRoundTripperWrapper(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"rg-1","name":"test"}`)
+	}))
+	defer server.Close()
+
+	var calls []string
+	wrapper := func(name string) RoundTripperWrapper {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				calls = append(calls, name+" request")
+				resp, err := next.RoundTrip(req)
+				calls = append(calls, name+" response")
+
+				return resp, err
+			})
+		}
+	}
+
+	c, err := New(server.URL, testToken(t), WithRoundTripperWrapper(wrapper("first")), WithRoundTripperWrapper(wrapper("second")))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := c.GetRuntimeGroup(context.Background(), "rg-1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := []string{"first request", "second request", "second response", "first response"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Fatalf("expected calls %v, got %v", want, calls)
+	}
+
+	if _, err := New(server.URL, testToken(t), WithRoundTripperWrapper(nil)); err == nil {
+		t.Fatal("expected an error for a nil wrapper")
+	}
+}
+
+func TestWithMaxConcurrency(t *testing.T) {
+	const max = 2
+
+	var inFlight, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		fmt.Fprint(w, `{"id":"rg-1","name":"test"}`)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t), WithMaxConcurrency(max))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if _, err := c.GetRuntimeGroup(context.Background(), "rg-1"); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak > max {
+		t.Fatalf("expected at most %d requests in flight, got %d", max, peak)
+	}
+
+	// A request waiting for a slot gives up with its context.
+	c.slots <- struct{}{}
+	c.slots <- struct{}{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := c.GetRuntimeGroup(ctx, "rg-1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected a deadline exceeded error, got %v", err)
+	}
+}
+
+func TestWithMaxConcurrencyShutdown(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(done)
+
+	base, shutdown := context.WithCancel(context.Background())
+	defer shutdown()
+
+	c, err := New(server.URL, testToken(t), WithMaxConcurrency(1), WithBaseContext(base))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// One request holds the only slot, the others queue behind it.
+	const callers = 5
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			_, err := c.GetRuntimeGroup(context.Background(), "rg-1")
+			errs <- err
+		}()
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	shutdown()
+
+	for i := 0; i < callers; i++ {
+		select {
+		case err := <-errs:
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("expected context.Canceled, got %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected every queued request to return on shutdown, %d did", i)
+		}
+	}
+
+	if n := len(c.slots); n != 0 {
+		t.Fatalf("expected every slot to be released, %d held", n)
+	}
+}
+
+func TestWithResponseValidator(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":"rg-1","name":"test","config":{"control_plane_endpoint":"http://cp.example.com"}}`)
+	}))
+	defer server.Close()
+
+	errInsecure := errors.New("insecure endpoint")
+	requireHTTPS := func(response any) error {
+		group, ok := response.(*CreateRuntimeGroupResponse)
+		if ok && !strings.HasPrefix(group.Config.ControlPlaneEndpoint, "https://") {
+			return fmt.Errorf("%w: %s", errInsecure, group.Config.ControlPlaneEndpoint)
+		}
+
+		return nil
+	}
+
+	c, err := New(server.URL, testToken(t), WithResponseValidator(requireHTTPS))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := c.GetRuntimeGroup(context.Background(), "rg-1"); !errors.Is(err, errInsecure) {
+		t.Fatalf("expected the validator error, got %v", err)
+	}
+
+	if _, err := c.CreateRuntimeGroup(CreateRuntimeGroupRequest{Name: "test"}); !errors.Is(err, errInsecure) {
+		t.Fatalf("expected the validator error, got %v", err)
+	}
+}
+
+func TestWithMethodOverride(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		t.Run(fmt.Sprint(enabled), func(t *testing.T) {
+			var methods, overrides []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				methods = append(methods, r.Method)
+				overrides = append(overrides, r.Header.Get("X-HTTP-Method-Override"))
+
+				if r.Header.Get("X-HTTP-Method-Override") == http.MethodDelete || r.Method == http.MethodDelete {
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+
+				fmt.Fprint(w, `{"id":"rg-1","name":"test"}`)
+			}))
+			defer server.Close()
+
+			c, err := New(server.URL, testToken(t), WithMethodOverride(enabled))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			ctx := context.Background()
+			if _, err := c.GetRuntimeGroup(ctx, "rg-1"); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if _, err := c.UpdateRuntimeGroup(ctx, "rg-1", UpdateRuntimeGroupRequest{Name: "test"}); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if err := c.DeleteRuntimeGroup(ctx, "rg-1"); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			wantMethods := []string{http.MethodGet, http.MethodPatch, http.MethodDelete}
+			wantOverrides := []string{"", "", ""}
+			if enabled {
+				wantMethods = []string{http.MethodGet, http.MethodPost, http.MethodPost}
+				wantOverrides = []string{"", http.MethodPatch, http.MethodDelete}
+			}
+
+			if !reflect.DeepEqual(methods, wantMethods) {
+				t.Errorf("expected methods %v, got %v", wantMethods, methods)
+			}
+
+			if !reflect.DeepEqual(overrides, wantOverrides) {
+				t.Errorf("expected overrides %q, got %q", wantOverrides, overrides)
+			}
+		})
+	}
+}
+
+func TestWithIdempotencyKeyFunc(t *testing.T) {
+	contentHash := func(req CreateRuntimeGroupRequest) string {
+		body, _ := json.Marshal(req)
+		return fmt.Sprintf("%x", sha256.Sum256(body))
+	}
+
+	tests := map[string]struct {
+		opts []Option
+		// wantSame reports whether identical requests share their key.
+		wantSame bool
+	}{
+		"random":       {},
+		"content hash": {opts: []Option{WithIdempotencyKeyFunc(contentHash)}, wantSame: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var keys []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				keys = append(keys, r.Header.Get("Idempotency-Key"))
+				fmt.Fprint(w, `{"id":"rg-1","name":"test"}`)
+			}))
+			defer server.Close()
+
+			c, err := New(server.URL, testToken(t), tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			for _, name := range []string{"test", "test", "other"} {
+				if _, err := c.CreateRuntimeGroup(CreateRuntimeGroupRequest{Name: name}); err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+			}
+
+			for _, key := range keys {
+				if key == "" {
+					t.Fatalf("expected idempotency keys, got %q", keys)
+				}
+			}
+
+			if (keys[0] == keys[1]) != tt.wantSame {
+				t.Errorf("expected identical requests to share their key %t, got %q", tt.wantSame, keys)
+			}
+
+			if keys[1] == keys[2] {
+				t.Errorf("expected different requests to have different keys, got %q", keys)
+			}
+		})
+	}
+}
+
+func TestWithDefaultClusterType(t *testing.T) {
+	c, err := New("https://example.com", testToken(t), WithDefaultClusterType(ClusterTypeHybrid))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got := c.DefaultClusterType(); got != ClusterTypeHybrid {
+		t.Fatalf("expected default cluster type %s, got %q", ClusterTypeHybrid, got)
+	}
+
+	if _, err := New("https://example.com", testToken(t), WithDefaultClusterType("CLUSTER_TYPE_UNKNOWN")); err == nil {
+		t.Fatal("expected an error for an unknown cluster type")
+	}
+}
+
+func TestWithRequestEncoder(t *testing.T) {
+	camelCase := func(body any) ([]byte, string, error) {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return nil, "", err
+		}
+
+		var fields map[string]any
+		if err := json.Unmarshal(encoded, &fields); err != nil {
+			return nil, "", err
+		}
+
+		renamed := make(map[string]any, len(fields))
+		for k, v := range fields {
+			parts := strings.Split(k, "_")
+			for i := 1; i < len(parts); i++ {
+				parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+			}
+			renamed[strings.Join(parts, "")] = v
+		}
+
+		encoded, err = json.Marshal(renamed)
+		return encoded, "application/vnd.konnect+json", err
+	}
+
+	var bodies, contentTypes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		contentTypes = append(contentTypes, r.Header.Get("Content-Type"))
+
+		fmt.Fprint(w, `{"id":"rg-1","name":"test"}`)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t), WithRequestEncoder(camelCase))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := c.CreateRuntimeGroup(CreateRuntimeGroupRequest{Name: "test", ClusterType: ClusterTypeHybrid}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := c.UpdateRuntimeGroup(context.Background(), "rg-1", UpdateRuntimeGroupRequest{Name: "test"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	wantBodies := []string{
+		`{"clusterType":"CLUSTER_TYPE_HYBRID","description":"","name":"test"}`,
+		`{"description":"","name":"test"}`,
+	}
+	if !reflect.DeepEqual(bodies, wantBodies) {
+		t.Fatalf("expected bodies %q, got %q", wantBodies, bodies)
+	}
+
+	for _, contentType := range contentTypes {
+		if contentType != "application/vnd.konnect+json" {
+			t.Fatalf("expected the Content-Type of the encoder, got %q", contentTypes)
+		}
+	}
+}
+
+func TestWithBaseContext(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(done)
+
+	type key struct{}
+	base, shutdown := context.WithCancel(context.WithValue(context.Background(), key{}, "base"))
+	defer shutdown()
+
+	var seen any
+	c, err := New(server.URL, testToken(t), WithBaseContext(base), WithRequestInterceptor(func(ctx context.Context, req *http.Request) error {
+		seen = ctx.Value(key{})
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// The call context has no deadline of its own, the base cancels it.
+	errs := make(chan error, 1)
+	go func() {
+		_, err := c.GetRuntimeGroup(context.Background(), "rg-1")
+		errs <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	shutdown()
+
+	select {
+	case err := <-errs:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the in-flight request to abort on shutdown")
+	}
+
+	if seen != "base" {
+		t.Fatalf("expected the base context value, got %v", seen)
+	}
+
+	// Requests after the shutdown abort as well.
+	if _, err := c.GetRuntimeGroup(context.Background(), "rg-1"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestResolveURL(t *testing.T) {
+	tests := map[string]struct {
+		baseURL  string
+		basePath string
+		endpoint string
+		want     string
+		wantErr  bool
+	}{
+		"base URL": {
+			baseURL:  "https://eu.api.konghq.com/v2",
+			endpoint: "/runtime-groups",
+			want:     "https://eu.api.konghq.com/v2/runtime-groups",
+		},
+		"base URL with trailing slash": {
+			baseURL:  "https://eu.api.konghq.com/v2/",
+			endpoint: "runtime-groups/rg-1",
+			want:     "https://eu.api.konghq.com/v2/runtime-groups/rg-1",
+		},
+		"base path": {
+			baseURL:  "https://gateway.example.com",
+			basePath: "/konnect/v2",
+			endpoint: "/runtime-groups/rg-1",
+			want:     "https://gateway.example.com/konnect/v2/runtime-groups/rg-1",
+		},
+		"base URL path and base path": {
+			baseURL:  "https://gateway.example.com/proxy",
+			basePath: "/konnect/v2/",
+			endpoint: "/runtime-groups",
+			want:     "https://gateway.example.com/proxy/konnect/v2/runtime-groups",
+		},
+		"escaped element": {
+			baseURL:  "https://eu.api.konghq.com/v2",
+			endpoint: "/runtime-groups/" + url.PathEscape("rg 1"),
+			want:     "https://eu.api.konghq.com/v2/runtime-groups/rg%201",
+		},
+		"query": {
+			baseURL:  "https://eu.api.konghq.com/v2",
+			endpoint: "/runtime-groups?page[size]=1",
+			want:     "https://eu.api.konghq.com/v2/runtime-groups?page[size]=1",
+		},
+		"absolute URL": {
+			baseURL:  "https://eu.api.konghq.com/v2",
+			endpoint: "https://example.com/runtime-groups",
+			wantErr:  true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := []Option{}
+			if tt.basePath != "" {
+				opts = append(opts, WithBasePath(tt.basePath))
+			}
+
+			c, err := New(tt.baseURL, testToken(t), opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			got, err := c.ResolveURL(tt.endpoint)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidEndpoint) {
+					t.Fatalf("expected ErrInvalidEndpoint, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if got != tt.want {
+				t.Fatalf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Package oteltrace adapts OpenTelemetry tracing to the client, see
+// client.WithTracer. It is kept apart from the client so that only the callers
+// tracing their requests depend on OpenTelemetry.
+package oteltrace
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/client"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation name of the spans of the client.
+const tracerName = "github.com/hashicorp/terraform-provider-scaffolding-framework/internal/client"
+
+// WithTracerProvider emits an OpenTelemetry client span per request from tp,
+// recording the method, host, status code and retry attempt of the request.
+// Client.Flush flushes tp when it implements client.Flusher, as the
+// TracerProvider of the OpenTelemetry SDK does.
+func WithTracerProvider(tp trace.TracerProvider) client.Option {
+	return func(c *client.Client) error {
+		if tp == nil {
+			return fmt.Errorf("tracer provider must not be nil")
+		}
+
+		return client.WithTracer(NewTracer(tp))(c)
+	}
+}
+
+// Tracer is a client.Tracer starting OpenTelemetry spans.
+type Tracer struct {
+	provider trace.TracerProvider
+	tracer   trace.Tracer
+}
+
+// NewTracer returns the client.Tracer starting the spans of tp.
+func NewTracer(tp trace.TracerProvider) *Tracer {
+	return &Tracer{provider: tp, tracer: tp.Tracer(tracerName)}
+}
+
+// Start starts a client span.
+func (t *Tracer) Start(ctx context.Context, name string) (context.Context, client.Span) {
+	ctx, span := t.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient))
+
+	return ctx, Span{span}
+}
+
+// ForceFlush flushes the tracer provider, if it implements client.Flusher.
+func (t *Tracer) ForceFlush(ctx context.Context) error {
+	if flusher, ok := t.provider.(client.Flusher); ok {
+		return flusher.ForceFlush(ctx)
+	}
+
+	return nil
+}
+
+// Span is a client.Span wrapping an OpenTelemetry span.
+type Span struct {
+	span trace.Span
+}
+
+// SetAttribute records a string or int attribute, other values are formatted
+// as strings.
+func (s Span) SetAttribute(key string, value any) {
+	switch v := value.(type) {
+	case string:
+		s.span.SetAttributes(attribute.String(key, v))
+	case int:
+		s.span.SetAttributes(attribute.Int(key, v))
+	default:
+		s.span.SetAttributes(attribute.String(key, fmt.Sprint(v)))
+	}
+}
+
+// RecordError records err as an event of the span.
+func (s Span) RecordError(err error) {
+	s.span.RecordError(err)
+}
+
+// SetError sets the status of the span to error.
+func (s Span) SetError(description string) {
+	s.span.SetStatus(codes.Error, description)
+}
+
+// End ends the span.
+func (s Span) End() {
+	s.span.End()
+}
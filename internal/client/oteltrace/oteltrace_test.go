@@ -0,0 +1,147 @@
+package oteltrace
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/client"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newClient returns a client of baseURL with opts, without checking its token.
+func newClient(t *testing.T, baseURL string, opts ...client.Option) *client.Client {
+	t.Helper()
+
+	c, err := client.New(baseURL, "test", append([]client.Option{client.WithSkipTokenValidation(true)}, opts...)...)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	return c
+}
+
+func TestWithTracerProvider(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		// Fail the first attempt to record a retry.
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	c := newClient(t, server.URL, WithTracerProvider(tp), client.WithRetry(1, time.Millisecond))
+
+	if _, err := c.GetRuntimeGroup(context.Background(), "rg-1"); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected a single span, got %d", len(spans))
+	}
+
+	span := spans[0]
+	if span.Name() != "HTTP GET" || span.SpanKind() != trace.SpanKindClient {
+		t.Errorf("expected client span HTTP GET, got %s span %s", span.SpanKind(), span.Name())
+	}
+
+	host, _ := url.Parse(server.URL)
+	want := map[attribute.Key]attribute.Value{
+		"http.request.method":       attribute.StringValue(http.MethodGet),
+		"server.address":            attribute.StringValue(host.Host),
+		"http.response.status_code": attribute.IntValue(http.StatusNotFound),
+		"http.resend_count":         attribute.IntValue(1),
+	}
+
+	got := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		got[kv.Key] = kv.Value
+	}
+
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("expected attribute %s=%s, got %s", key, value.Emit(), got[key].Emit())
+		}
+	}
+
+	if span.Status().Code != codes.Error {
+		t.Errorf("expected an error status, got %v", span.Status())
+	}
+}
+
+func TestWithTracerProviderNil(t *testing.T) {
+	if _, err := client.New("https://example.com", "test", client.WithSkipTokenValidation(true), WithTracerProvider(nil)); err == nil {
+		t.Fatal("expected an error for a nil tracer provider")
+	}
+}
+
+// The tracer provider of the OpenTelemetry SDK is flushed by Client.Flush.
+var _ client.Flusher = (*sdktrace.TracerProvider)(nil)
+
+// flushingTracerProvider is a tracer provider recording its flushes.
+type flushingTracerProvider struct {
+	trace.TracerProvider
+	flushes int
+	err     error
+}
+
+func (p *flushingTracerProvider) ForceFlush(ctx context.Context) error {
+	p.flushes++
+	return p.err
+}
+
+func TestFlush(t *testing.T) {
+	tests := map[string]struct {
+		provider *flushingTracerProvider
+		wantErr  bool
+	}{
+		"flushable": {
+			provider: &flushingTracerProvider{TracerProvider: sdktrace.NewTracerProvider()},
+		},
+		"flush error": {
+			provider: &flushingTracerProvider{TracerProvider: sdktrace.NewTracerProvider(), err: errors.New("export failed")},
+			wantErr:  true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := newClient(t, "https://example.com", WithTracerProvider(tt.provider))
+
+			if err := c.Flush(context.Background()); (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %t, got %v", tt.wantErr, err)
+			}
+
+			if tt.provider.flushes != 1 {
+				t.Fatalf("expected 1 flush, got %d", tt.provider.flushes)
+			}
+		})
+	}
+}
+
+func TestFlushNotFlushable(t *testing.T) {
+	// The provider is not a Flusher, Flush is a no-op.
+	c := newClient(t, "https://example.com", WithTracerProvider(trace.NewNoopTracerProvider()))
+
+	if err := c.Flush(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
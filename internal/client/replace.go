@@ -0,0 +1,61 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// replaceRuntimeGroupMethod is the HTTP method for replacing a runtime group.
+const replaceRuntimeGroupMethod = http.MethodPut
+
+// ReplaceRuntimeGroup sends a PUT request replacing the runtime group with the
+// given id by the request. Unlike UpdateRuntimeGroup, the fields missing from
+// the request are reset by the API, and nil labels are sent as {}, removing
+// them all. ErrNotSupported is returned when the API does not accept PUT.
+func (c *Client) ReplaceRuntimeGroup(ctx context.Context, id string, requestBody CreateRuntimeGroupRequest) (*CreateRuntimeGroupResponse, error) {
+	defer c.locks.lock(id)()
+
+	c.cache.invalidate(id)
+	defer c.cache.invalidate(id)
+
+	if err := requestBody.Validate(); err != nil {
+		return nil, c.wrap("validating request body", err)
+	}
+
+	if requestBody.Labels == nil {
+		requestBody.Labels = map[string]string{}
+	}
+	requestBody.Labels = c.mergeDefaultLabels(requestBody.Labels, labelVars{Name: requestBody.Name, ClusterType: requestBody.ClusterType})
+
+	requestBodyBytes, contentType, err := c.encoder(requestBody)
+	if err != nil {
+		return nil, c.wrap("serializing request body", err)
+	}
+
+	endpoint, err := c.endpoint(OperationReplaceRuntimeGroup, runtimeGroupEndpoint, url.PathEscape(id))
+	if err != nil {
+		return nil, c.wrap("joining base URL and endpoint", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, replaceRuntimeGroupMethod, endpoint, bytes.NewBuffer(requestBodyBytes))
+	if err != nil {
+		return nil, c.wrap("creating HTTP request", err)
+	}
+
+	req.Header.Set("Content-Type", contentType)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, c.wrap("making HTTP request", err)
+	}
+	defer resp.Body.Close()
+
+	// Check the HTTP response status code.
+	if err := c.checkResponse(resp); err != nil {
+		return nil, c.wrap("checking status code", err)
+	}
+
+	return c.readRuntimeGroup(req.Context(), resp, id)
+}
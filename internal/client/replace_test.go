@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestReplaceRuntimeGroup(t *testing.T) {
+	tests := map[string]struct {
+		request CreateRuntimeGroupRequest
+		want    map[string]any
+	}{
+		"with labels": {
+			request: CreateRuntimeGroupRequest{Name: "test", ClusterType: ClusterTypeHybrid, Labels: map[string]string{"env": "dev"}},
+			want:    map[string]any{"name": "test", "description": "", "cluster_type": "CLUSTER_TYPE_HYBRID", "labels": map[string]any{"env": "dev"}},
+		},
+		"nil labels": {
+			request: CreateRuntimeGroupRequest{Name: "test", Description: "desc"},
+			want:    map[string]any{"name": "test", "description": "desc", "cluster_type": "", "labels": map[string]any{}},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got map[string]any
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPut || r.URL.Path != "/runtime-groups/rg-1" {
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+				}
+
+				if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+					t.Errorf("decoding request body: %s", err)
+				}
+
+				fmt.Fprint(w, `{"id":"rg-1","name":"test"}`)
+			}))
+			defer server.Close()
+
+			c, err := New(server.URL, testToken(t))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			group, err := c.ReplaceRuntimeGroup(context.Background(), "rg-1", tt.request)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if group.ID != "rg-1" {
+				t.Fatalf("unexpected runtime group: %+v", group)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected body %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestReplaceRuntimeGroupNotSupported(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := c.ReplaceRuntimeGroup(context.Background(), "rg-1", CreateRuntimeGroupRequest{Name: "test"}); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("expected ErrNotSupported, got %v", err)
+	}
+}
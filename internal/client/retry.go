@@ -0,0 +1,135 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// BackoffStrategy paces the retries of transient failures, see WithBackoff.
+type BackoffStrategy interface {
+	// Backoff returns the delay before the given retry attempt, starting at 1,
+	// from the base delay set by WithRetry.
+	Backoff(base time.Duration, attempt int) time.Duration
+}
+
+// ConstantBackoff waits the base delay before every retry.
+type ConstantBackoff struct{}
+
+func (ConstantBackoff) Backoff(base time.Duration, attempt int) time.Duration {
+	return base
+}
+
+// LinearBackoff waits the base delay times the attempt, e.g. 1s, 2s, 3s.
+type LinearBackoff struct{}
+
+func (LinearBackoff) Backoff(base time.Duration, attempt int) time.Duration {
+	return base * time.Duration(attempt)
+}
+
+// ExponentialBackoff doubles the delay on every attempt, e.g. 1s, 2s, 4s. With
+// Jitter, a random delay of up to half of it is cut off, so that clients
+// failing together do not retry in lockstep.
+type ExponentialBackoff struct {
+	Jitter bool
+}
+
+func (b ExponentialBackoff) Backoff(base time.Duration, attempt int) time.Duration {
+	delay := base << (attempt - 1)
+	if !b.Jitter || delay < 2 {
+		return delay
+	}
+
+	return delay - jitter(delay/2)
+}
+
+// jitterRand is the random source of the backoff jitter, shared by the clients.
+var jitterRand = struct {
+	sync.Mutex
+	*rand.Rand
+}{Rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+
+// jitter returns a random duration in [0, max).
+func jitter(max time.Duration) time.Duration {
+	jitterRand.Lock()
+	defer jitterRand.Unlock()
+
+	return time.Duration(jitterRand.Int63n(int64(max)))
+}
+
+// shouldRetry reports whether the outcome of an attempt is a transient failure.
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		return retryableError(err)
+	}
+
+	return retryableStatus(resp.StatusCode)
+}
+
+// retryableStatus reports whether a response status code is transient. Some
+// servers answer slow requests with 408 Request Timeout rather than resetting
+// the connection.
+func retryableStatus(code int) bool {
+	if code == http.StatusRequestTimeout || code == http.StatusTooManyRequests {
+		return true
+	}
+
+	return code >= http.StatusInternalServerError && code != http.StatusNotImplemented
+}
+
+// retryableError reports whether a transport error is transient: connections
+// reset or closed mid-request are retried, DNS resolution failures are not as
+// they most likely come from a misconfigured base URL.
+func retryableError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return false
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// canRetry reports whether the request can be sent again, i.e. it has no body
+// or its body can be re-read.
+func canRetry(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// waitRetry rewinds the body of the request and waits for the backoff of the
+// given attempt, returning early when the request context is done.
+func (c *Client) waitRetry(req *http.Request, attempt int) error {
+	if err := rewindBody(req); err != nil {
+		return err
+	}
+
+	return c.clock.Sleep(req.Context(), c.backoff.Backoff(c.retryBackoff, attempt))
+}
+
+// rewindBody replaces the consumed body of the request with a fresh copy.
+func rewindBody(req *http.Request) error {
+	if req.GetBody == nil {
+		return nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return fmt.Errorf("rewinding request body: %w", err)
+	}
+	req.Body = body
+
+	return nil
+}
+
+// drainAndClose discards the rest of the body so the connection can be reused.
+func drainAndClose(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
@@ -0,0 +1,214 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRetryConnectionReset(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			// Reset the connection: closing with a zero linger sends a RST.
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Errorf("hijacking connection: %s", err)
+				return
+			}
+			_ = conn.(*net.TCPConn).SetLinger(0)
+			conn.Close()
+			return
+		}
+
+		fmt.Fprint(w, `{"id":"rg-1"}`)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t), WithRetry(2, time.Millisecond))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := c.GetRuntimeGroup(context.Background(), "rg-1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Fatalf("expected 2 requests, got %d", got)
+	}
+}
+
+func TestRetryRequestTimeout(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("reading request body: %s", err)
+		}
+
+		if want := `"name":"test"`; !strings.Contains(string(body), want) {
+			t.Errorf("expected body to contain %s, got %s", want, body)
+		}
+
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.WriteHeader(http.StatusRequestTimeout)
+			return
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":"rg-1","name":"test"}`)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t), WithRetry(2, time.Millisecond))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := c.CreateRuntimeGroup(CreateRuntimeGroupRequest{Name: "test"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Fatalf("expected 2 requests, got %d", got)
+	}
+}
+
+func TestRetryDisabledByDefault(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := c.GetRuntimeGroup(context.Background(), "rg-1"); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Fatalf("expected 1 request, got %d", got)
+	}
+}
+
+func TestRetryableError(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want bool
+	}{
+		"connection reset": {
+			err:  &url.Error{Op: "Get", Err: &net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}},
+			want: true,
+		},
+		"broken pipe": {
+			err:  &url.Error{Op: "Post", Err: &net.OpError{Op: "write", Err: os.NewSyscallError("write", syscall.EPIPE)}},
+			want: true,
+		},
+		"unexpected EOF": {
+			err:  &url.Error{Op: "Get", Err: io.ErrUnexpectedEOF},
+			want: true,
+		},
+		"DNS failure": {
+			err:  &url.Error{Op: "Get", Err: &net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", Name: "example.invalid", IsNotFound: true}}},
+			want: false,
+		},
+		"connection refused": {
+			err:  &url.Error{Op: "Get", Err: &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}},
+			want: false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := retryableError(test.err); got != test.want {
+				t.Fatalf("expected %t, got %t", test.want, got)
+			}
+		})
+	}
+}
+
+func TestWithBackoff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	tests := map[string]struct {
+		strategy BackoffStrategy
+		want     []time.Duration
+	}{
+		"constant":    {strategy: ConstantBackoff{}, want: []time.Duration{time.Second, time.Second, time.Second, time.Second}},
+		"linear":      {strategy: LinearBackoff{}, want: []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 4 * time.Second}},
+		"exponential": {strategy: ExponentialBackoff{}, want: []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			clk := &fakeClock{now: time.Now()}
+			c, err := New(server.URL, testToken(t), WithRetry(4, time.Second), WithBackoff(tt.strategy), WithClock(clk))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if _, err := c.GetRuntimeGroup(context.Background(), "rg-1"); err == nil {
+				t.Fatal("expected error, got nil")
+			}
+
+			if !reflect.DeepEqual(clk.sleeps, tt.want) {
+				t.Fatalf("expected backoff %v, got %v", tt.want, clk.sleeps)
+			}
+		})
+	}
+}
+
+func TestDefaultBackoffJitter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	clk := &fakeClock{now: time.Now()}
+	c, err := New(server.URL, testToken(t), WithRetry(4, time.Second), WithClock(clk))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := c.GetRuntimeGroup(context.Background(), "rg-1"); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if len(clk.sleeps) != 4 {
+		t.Fatalf("expected 4 retries, got %v", clk.sleeps)
+	}
+
+	// Every delay is within half of the exponential delay, and below it.
+	for i, sleep := range clk.sleeps {
+		exponential := time.Second << i
+		if sleep < exponential/2 || sleep > exponential {
+			t.Fatalf("expected delay %d in [%s, %s], got %s", i+1, exponential/2, exponential, sleep)
+		}
+	}
+}
+
+func TestWithBackoffNil(t *testing.T) {
+	if _, err := New("https://example.com", testToken(t), WithBackoff(nil)); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
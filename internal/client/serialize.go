@@ -0,0 +1,52 @@
+package client
+
+import "sync"
+
+// groupLocks serializes the mutating operations on the same runtime group,
+// while operations on different runtime groups run concurrently. A nil
+// groupLocks serializes nothing.
+type groupLocks struct {
+	mu    sync.Mutex
+	locks map[string]*groupLock
+}
+
+// groupLock is the lock of a runtime group, dropped by the last user.
+type groupLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// lock blocks until the runtime group with the given id is free and returns
+// the function releasing it.
+func (gl *groupLocks) lock(id string) func() {
+	if gl == nil {
+		return func() {}
+	}
+
+	gl.mu.Lock()
+	if gl.locks == nil {
+		gl.locks = make(map[string]*groupLock)
+	}
+
+	l, ok := gl.locks[id]
+	if !ok {
+		l = &groupLock{}
+		gl.locks[id] = l
+	}
+	l.refs++
+	gl.mu.Unlock()
+
+	l.mu.Lock()
+
+	return func() {
+		l.mu.Unlock()
+
+		gl.mu.Lock()
+		defer gl.mu.Unlock()
+
+		l.refs--
+		if l.refs == 0 {
+			delete(gl.locks, id)
+		}
+	}
+}
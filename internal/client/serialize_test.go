@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithPerResourceSerialization(t *testing.T) {
+	tests := map[string]struct {
+		ids         []string
+		opts        []Option
+		wantOverlap bool
+	}{
+		"same runtime group": {
+			ids:  []string{"rg-1", "rg-1"},
+			opts: []Option{WithPerResourceSerialization(true)},
+		},
+		"different runtime groups": {
+			ids:         []string{"rg-1", "rg-2"},
+			opts:        []Option{WithPerResourceSerialization(true)},
+			wantOverlap: true,
+		},
+		"disabled": {
+			ids:         []string{"rg-1", "rg-1"},
+			wantOverlap: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var inFlight, maxInFlight int32
+			// arrived is closed once every request reached the server, so
+			// that requests allowed to overlap wait for each other.
+			arrived := make(chan struct{})
+			var arrivals int32
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := atomic.AddInt32(&inFlight, 1)
+				defer atomic.AddInt32(&inFlight, -1)
+
+				for {
+					m := atomic.LoadInt32(&maxInFlight)
+					if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+						break
+					}
+				}
+
+				if atomic.AddInt32(&arrivals, 1) == int32(len(tt.ids)) {
+					close(arrived)
+				}
+
+				select {
+				case <-arrived:
+				case <-time.After(100 * time.Millisecond):
+				}
+
+				fmt.Fprintf(w, `{"id":%q}`, strings.TrimPrefix(r.URL.Path, "/runtime-groups/"))
+			}))
+			defer server.Close()
+
+			c, err := New(server.URL, testToken(t), tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			var wg sync.WaitGroup
+			for _, id := range tt.ids {
+				wg.Add(1)
+				go func(id string) {
+					defer wg.Done()
+
+					if _, err := c.UpdateRuntimeGroup(context.Background(), id, UpdateRuntimeGroupRequest{Name: "name"}); err != nil {
+						t.Errorf("unexpected error: %s", err)
+					}
+				}(id)
+			}
+			wg.Wait()
+
+			if got := maxInFlight > 1; got != tt.wantOverlap {
+				t.Errorf("expected overlapping updates %t, got %d in flight", tt.wantOverlap, maxInFlight)
+			}
+		})
+	}
+}
+
+func TestGroupLocksRelease(t *testing.T) {
+	gl := &groupLocks{}
+
+	unlock := gl.lock("rg-1")
+	if len(gl.locks) != 1 {
+		t.Fatalf("expected 1 lock, got %d", len(gl.locks))
+	}
+	unlock()
+
+	if len(gl.locks) != 0 {
+		t.Errorf("expected the released lock to be dropped, got %d locks", len(gl.locks))
+	}
+
+	// A nil groupLocks serializes nothing.
+	var none *groupLocks
+	none.lock("rg-1")()
+	none.lock("rg-1")()
+}
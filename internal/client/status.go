@@ -0,0 +1,91 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+const (
+	// runtimeGroupStatusEndpoint is the subresource of a runtime group holding its status.
+	runtimeGroupStatusEndpoint = "status"
+
+	// getRuntimeGroupStatusMethod is the HTTP method for fetching the status of a runtime group.
+	getRuntimeGroupStatusMethod = http.MethodGet
+)
+
+// RuntimeGroupStatus represents the data plane connectivity status of a runtime group.
+type RuntimeGroupStatus struct {
+	State     string `json:"state"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+// GetRuntimeGroupStatus sends a GET request to fetch the status of the runtime
+// group with the given id. ErrNotFound or ErrNotSupported is returned when the
+// API does not expose the status subresource.
+func (c *Client) GetRuntimeGroupStatus(ctx context.Context, id string) (*RuntimeGroupStatus, error) {
+	endpoint, err := c.endpoint(OperationGetRuntimeGroupStatus, runtimeGroupEndpoint, url.PathEscape(id), runtimeGroupStatusEndpoint)
+	if err != nil {
+		return nil, c.wrap("joining base URL and endpoint", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, getRuntimeGroupStatusMethod, endpoint, nil)
+	if err != nil {
+		return nil, c.wrap("creating HTTP request", err)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, c.wrap("making HTTP request", err)
+	}
+	defer resp.Body.Close()
+
+	// Check the HTTP response status code.
+	if err := c.checkResponse(resp); err != nil {
+		return nil, c.wrap("checking status code", err)
+	}
+
+	var status RuntimeGroupStatus
+	if _, err := readJSON(resp.Body, &status); err != nil {
+		return nil, c.wrap("decoding response JSON", err)
+	}
+
+	if err := c.validateResponse(&status); err != nil {
+		return nil, err
+	}
+
+	return &status, nil
+}
+
+// GetRuntimeGroupStatuses fetches the statuses of the runtime groups with the
+// given ids, keyed by id, sending at most maxConcurrentGets requests at once,
+// e.g. for the runtime groups listed without their status. Runtime groups
+// whose status the API does not expose are left out.
+func (c *Client) GetRuntimeGroupStatuses(ctx context.Context, ids []string) (map[string]*RuntimeGroupStatus, error) {
+	var mu sync.Mutex
+	statuses := make(map[string]*RuntimeGroupStatus, len(ids))
+
+	err := c.fetchConcurrently(ctx, ids, "status of runtime group", func(id string) error {
+		status, err := c.GetRuntimeGroupStatus(ctx, id)
+		if errors.Is(err, ErrNotFound) || errors.Is(err, ErrNotSupported) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		statuses[id] = status
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return statuses, nil
+}
@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetRuntimeGroupStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/runtime-groups/rg-1/status":
+			fmt.Fprint(w, `{"state":"connected","updated_at":"2022-11-04T20:10:06.927Z"}`)
+		case "/runtime-groups/rg-2/status":
+			w.WriteHeader(http.StatusNotImplemented)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	status, err := c.GetRuntimeGroupStatus(context.Background(), "rg-1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if status.State != "connected" || status.UpdatedAt != "2022-11-04T20:10:06.927Z" {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+
+	if _, err := c.GetRuntimeGroupStatus(context.Background(), "rg-2"); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("expected ErrNotSupported, got: %v", err)
+	}
+
+	if _, err := c.GetRuntimeGroupStatus(context.Background(), "rg-3"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got: %v", err)
+	}
+}
+
+func TestGetRuntimeGroupStatuses(t *testing.T) {
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		switch r.URL.Path {
+		case "/runtime-groups/missing/status":
+			w.WriteHeader(http.StatusNotFound)
+		case "/runtime-groups/failing/status":
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			fmt.Fprintf(w, `{"state":%q}`, strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/runtime-groups/"), "/status"))
+		}
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ids := []string{"missing"}
+	for i := 0; i < 2*maxConcurrentGets; i++ {
+		ids = append(ids, fmt.Sprintf("rg-%d", i))
+	}
+
+	statuses, err := c.GetRuntimeGroupStatuses(context.Background(), ids)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(statuses) != 2*maxConcurrentGets || statuses["rg-3"].State != "rg-3" {
+		t.Fatalf("expected the statuses of the existing runtime groups, got %v", statuses)
+	}
+
+	if _, ok := statuses["missing"]; ok {
+		t.Errorf("expected no status for the missing runtime group")
+	}
+
+	if maxInFlight < 2 || maxInFlight > maxConcurrentGets {
+		t.Errorf("expected between 2 and %d requests in flight, got %d", maxConcurrentGets, maxInFlight)
+	}
+
+	if _, err := c.GetRuntimeGroupStatuses(context.Background(), []string{"rg-1", "failing"}); err == nil {
+		t.Fatal("expected an error")
+	}
+}
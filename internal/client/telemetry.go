@@ -0,0 +1,120 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// MetricsRecorder records the outcome of every request, see
+// WithMetricsRecorder. Recorders buffering what they record implement Flusher
+// too, so that Client.Flush exports it.
+type MetricsRecorder interface {
+	// RecordRequest records a request sent with method, the status code of its
+	// response, zero when it failed without one, how long it took including
+	// the retries, and the error it failed with, if any.
+	RecordRequest(ctx context.Context, method string, statusCode int, duration time.Duration, err error)
+}
+
+// Tracer starts a span around every request, see WithTracer. The oteltrace
+// package adapts an OpenTelemetry TracerProvider, so that the client itself
+// does not depend on OpenTelemetry.
+type Tracer interface {
+	// Start starts a client span named name, returning ctx holding it.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is the span of a request started by a Tracer.
+type Span interface {
+	// SetAttribute records an attribute of the request, a string or an int.
+	SetAttribute(key string, value any)
+	// RecordError records the error the request failed with.
+	RecordError(err error)
+	// SetError marks the span as failed with description.
+	SetError(description string)
+	// End ends the span.
+	End()
+}
+
+// spanKey is the context key of the span of a request.
+type spanKey struct{}
+
+// Flusher is implemented by the metrics recorders and tracers buffering what
+// they record before exporting it, e.g. the TracerProvider of the OpenTelemetry
+// SDK.
+type Flusher interface {
+	// ForceFlush exports the buffered telemetry, giving up when ctx is done.
+	ForceFlush(ctx context.Context) error
+}
+
+// Flush exports the metrics buffered by the recorder set with
+// WithMetricsRecorder, then the spans buffered by the tracer set with
+// WithTracer, e.g. so that they are not lost when the process exits. Those not
+// implementing Flusher are skipped. Both are flushed even if the first fails,
+// the first error is returned.
+func (c *Client) Flush(ctx context.Context) error {
+	var first error
+	for _, telemetry := range []struct {
+		name string
+		v    any
+	}{
+		{"metrics", c.metrics},
+		{"traces", c.tracer},
+	} {
+		flusher, ok := telemetry.v.(Flusher)
+		if !ok {
+			continue
+		}
+
+		if err := flusher.ForceFlush(ctx); err != nil && first == nil {
+			first = c.wrap("flushing "+telemetry.name, err)
+		}
+	}
+
+	return first
+}
+
+// recordRequest records req, sent since start, with the metrics recorder.
+func (c *Client) recordRequest(req *http.Request, resp *http.Response, err error, start time.Time) {
+	if c.metrics == nil {
+		return
+	}
+
+	statusCode := 0
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+
+	c.metrics.RecordRequest(req.Context(), req.Method, statusCode, c.clock.Now().Sub(start), err)
+}
+
+// doWithSpan performs the request within a client span recording its method,
+// host and status code, and the error when it fails.
+func (c *Client) doWithSpan(req *http.Request) (*http.Response, error) {
+	ctx, span := c.tracer.Start(req.Context(), "HTTP "+req.Method)
+	defer span.End()
+
+	span.SetAttribute("http.request.method", req.Method)
+	span.SetAttribute("server.address", req.URL.Host)
+
+	resp, err := c.doRequest(req.WithContext(context.WithValue(ctx, spanKey{}, span)))
+	if err != nil {
+		span.RecordError(err)
+		span.SetError(err.Error())
+		return nil, err
+	}
+
+	span.SetAttribute("http.response.status_code", resp.StatusCode)
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetError(http.StatusText(resp.StatusCode))
+	}
+
+	return resp, nil
+}
+
+// recordAttempt records the retry attempt of req on its span, if any.
+func recordAttempt(req *http.Request, attempt int) {
+	if span, ok := req.Context().Value(spanKey{}).(Span); ok {
+		span.SetAttribute("http.resend_count", attempt)
+	}
+}
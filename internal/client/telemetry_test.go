@@ -0,0 +1,250 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// recordingTracer is a Tracer recording its spans.
+type recordingTracer struct {
+	spans   []*recordingSpan
+	flushes int
+	err     error
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := &recordingSpan{name: name, attributes: make(map[string]any)}
+	t.spans = append(t.spans, span)
+
+	return ctx, span
+}
+
+func (t *recordingTracer) ForceFlush(ctx context.Context) error {
+	t.flushes++
+	return t.err
+}
+
+// recordingSpan is a Span recording what is set on it.
+type recordingSpan struct {
+	name       string
+	attributes map[string]any
+	errs       []error
+	failed     string
+	ended      bool
+}
+
+func (s *recordingSpan) SetAttribute(key string, value any) { s.attributes[key] = value }
+func (s *recordingSpan) RecordError(err error)              { s.errs = append(s.errs, err) }
+func (s *recordingSpan) SetError(description string)        { s.failed = description }
+func (s *recordingSpan) End()                               { s.ended = true }
+
+func TestWithTracer(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		// Fail the first attempt to record a retry.
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	tracer := &recordingTracer{}
+	c, err := New(server.URL, testToken(t), WithTracer(tracer), WithRetry(1, time.Millisecond))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := c.GetRuntimeGroup(context.Background(), "rg-1"); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if len(tracer.spans) != 1 {
+		t.Fatalf("expected a single span, got %d", len(tracer.spans))
+	}
+
+	span := tracer.spans[0]
+	if span.name != "HTTP GET" || !span.ended {
+		t.Errorf("expected the ended span HTTP GET, got %+v", span)
+	}
+
+	host, _ := url.Parse(server.URL)
+	want := map[string]any{
+		"http.request.method":       http.MethodGet,
+		"server.address":            host.Host,
+		"http.response.status_code": http.StatusNotFound,
+		"http.resend_count":         1,
+	}
+	if !reflect.DeepEqual(span.attributes, want) {
+		t.Errorf("expected attributes %v, got %v", want, span.attributes)
+	}
+
+	if span.failed != http.StatusText(http.StatusNotFound) {
+		t.Errorf("expected the span failed with %q, got %q", http.StatusText(http.StatusNotFound), span.failed)
+	}
+}
+
+func TestWithTracerRequestError(t *testing.T) {
+	tracer := &recordingTracer{}
+	c, err := New("http://127.0.0.1:1", testToken(t), WithTracer(tracer))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := c.GetRuntimeGroup(context.Background(), "rg-1"); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if span := tracer.spans[0]; len(span.errs) != 1 || span.failed == "" {
+		t.Fatalf("expected the error recorded on the span, got %+v", span)
+	}
+
+	if _, err := New("https://example.com", testToken(t), WithTracer(nil)); err == nil {
+		t.Fatal("expected an error for a nil tracer")
+	}
+}
+
+// recordedRequest is a request recorded by a MetricsRecorder.
+type recordedRequest struct {
+	method     string
+	statusCode int
+	duration   time.Duration
+	failed     bool
+}
+
+// recordingMetrics is a MetricsRecorder recording its requests.
+type recordingMetrics struct {
+	requests []recordedRequest
+}
+
+func (m *recordingMetrics) RecordRequest(ctx context.Context, method string, statusCode int, duration time.Duration, err error) {
+	m.requests = append(m.requests, recordedRequest{method: method, statusCode: statusCode, duration: duration, failed: err != nil})
+}
+
+// flushingMetrics is a MetricsRecorder buffering its requests until flushed.
+type flushingMetrics struct {
+	recordingMetrics
+	flushes int
+	err     error
+}
+
+func (m *flushingMetrics) ForceFlush(ctx context.Context) error {
+	m.flushes++
+	return m.err
+}
+
+func TestWithMetricsRecorder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	metrics := &recordingMetrics{}
+	c, err := New(server.URL, testToken(t), WithMetricsRecorder(metrics))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ctx := context.Background()
+	if _, err := c.GetRuntimeGroup(ctx, "rg-1"); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	server.Close()
+	if err := c.DeleteRuntimeGroup(ctx, "rg-1"); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if len(metrics.requests) != 2 {
+		t.Fatalf("expected 2 recorded requests, got %+v", metrics.requests)
+	}
+
+	if got := metrics.requests[0]; got.method != http.MethodGet || got.statusCode != http.StatusNotFound || got.failed {
+		t.Errorf("expected GET with status 404, got %+v", got)
+	}
+
+	if got := metrics.requests[1]; got.method != http.MethodDelete || got.statusCode != 0 || !got.failed {
+		t.Errorf("expected a failed DELETE without status, got %+v", got)
+	}
+
+	if _, err := New("https://example.com", testToken(t), WithMetricsRecorder(nil)); err == nil {
+		t.Fatal("expected an error for a nil metrics recorder")
+	}
+}
+
+func TestFlush(t *testing.T) {
+	tests := map[string]struct {
+		metrics     MetricsRecorder
+		tracer      *recordingTracer
+		wantFlushes int
+		wantErr     bool
+	}{
+		"nothing set": {},
+		"flushable recorder": {
+			metrics:     &flushingMetrics{},
+			wantFlushes: 1,
+		},
+		"recorder not flushable": {
+			metrics: &recordingMetrics{},
+		},
+		"recorder flush error": {
+			metrics:     &flushingMetrics{err: errors.New("export failed")},
+			tracer:      &recordingTracer{},
+			wantFlushes: 2,
+			wantErr:     true,
+		},
+		"flushable tracer": {
+			tracer:      &recordingTracer{},
+			wantFlushes: 1,
+		},
+		"tracer flush error": {
+			tracer:      &recordingTracer{err: errors.New("export failed")},
+			wantFlushes: 1,
+			wantErr:     true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var opts []Option
+			if tt.metrics != nil {
+				opts = append(opts, WithMetricsRecorder(tt.metrics))
+			}
+			if tt.tracer != nil {
+				opts = append(opts, WithTracer(tt.tracer))
+			}
+
+			c, err := New("https://example.com", testToken(t), opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if err := c.Flush(context.Background()); (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %t, got %v", tt.wantErr, err)
+			}
+
+			// Both are flushed, even when the first fails.
+			flushes := 0
+			if m, ok := tt.metrics.(*flushingMetrics); ok {
+				flushes += m.flushes
+			}
+			if tt.tracer != nil {
+				flushes += tt.tracer.flushes
+			}
+
+			if flushes != tt.wantFlushes {
+				t.Fatalf("expected %d flushes, got %d", tt.wantFlushes, flushes)
+			}
+		})
+	}
+}
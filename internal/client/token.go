@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// TokenSource supplies bearer tokens to the Client, e.g. to rotate short-lived
+// credentials. Token is called whenever the cached token is missing or no
+// longer valid.
+type TokenSource interface {
+	Token() (string, error)
+}
+
+// Identity holds the claims of the bearer token identifying the caller. Claims
+// missing from the token are left empty.
+type Identity struct {
+	// Subject is the "sub" claim, the user or system account of the token.
+	Subject string
+	// OrganizationID is the "org_id" claim, the Konnect organization.
+	OrganizationID string
+	// Scopes are read from the space separated "scope" claim, or else from the
+	// "scp" list claim.
+	Scopes []string
+}
+
+// Identity decodes the claims of the current bearer token without contacting
+// the API. The signature of the token is not verified.
+func (c *Client) Identity() (*Identity, error) {
+	token, err := c.currentToken()
+	if err != nil {
+		return nil, c.wrap("getting bearer token", err)
+	}
+
+	claims := jwt.MapClaims{}
+	if _, _, err := new(jwt.Parser).ParseUnverified(token, claims); err != nil {
+		return nil, c.wrap("parsing bearer token", err)
+	}
+
+	identity := &Identity{
+		Subject:        stringClaim(claims, "sub"),
+		OrganizationID: stringClaim(claims, "org_id"),
+	}
+
+	if scope := stringClaim(claims, "scope"); scope != "" {
+		identity.Scopes = strings.Fields(scope)
+	} else if scopes, ok := claims["scp"].([]interface{}); ok {
+		for _, scope := range scopes {
+			if scope, ok := scope.(string); ok {
+				identity.Scopes = append(identity.Scopes, scope)
+			}
+		}
+	}
+
+	return identity, nil
+}
+
+// ValidateToken checks the bearer token against the API with a lightweight
+// authenticated request, listing a single runtime group, unlike the local
+// checks of New. An error matching ErrUnauthorized is returned when the API
+// rejects the token with 401 Unauthorized or 403 Forbidden.
+func (c *Client) ValidateToken(ctx context.Context) error {
+	_, err := c.ListRuntimeGroups(ctx, ListOptions{PageSize: 1})
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden) {
+		return c.wrap("validating token", fmt.Errorf("%w: the API rejected the token with status code %d, "+
+			"check that it is valid, not expired and allowed to list runtime groups", ErrUnauthorized, apiErr.StatusCode))
+	}
+	if err != nil {
+		return c.wrap("validating token", err)
+	}
+
+	return nil
+}
+
+// stringClaim returns the claim with the given name, or an empty string when
+// it is missing or not a string.
+func stringClaim(claims jwt.MapClaims, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+// SetToken replaces the bearer token used by subsequent requests. It is safe
+// to call while requests are in flight.
+func (c *Client) SetToken(token string) error {
+	if err := validateBearerToken(token, c.clock.Now()); err != nil {
+		return c.wrap("error validating bearer token", err)
+	}
+
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	c.token = token
+
+	return nil
+}
+
+// currentToken returns the cached bearer token, sourcing a fresh one when the
+// cached token is missing or invalid and a TokenSource is configured.
+func (c *Client) currentToken() (string, error) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	if c.tokenSource == nil || (c.token != "" && validateBearerToken(c.token, c.clock.Now()) == nil) {
+		return c.token, nil
+	}
+
+	token, err := c.tokenSource.Token()
+	if err != nil {
+		return "", fmt.Errorf("sourcing bearer token: %w", err)
+	}
+
+	if err := validateBearerToken(token, c.clock.Now()); err != nil {
+		return "", fmt.Errorf("validating sourced bearer token: %w", err)
+	}
+
+	c.token = token
+
+	return token, nil
+}
+
+// invalidateToken drops the cached bearer token, so the next request sources
+// a fresh one from the TokenSource.
+func (c *Client) invalidateToken() {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	c.token = ""
+}
@@ -0,0 +1,321 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// staticTokenSource hands out a fixed token and counts how often it is asked.
+type staticTokenSource struct {
+	mu    sync.Mutex
+	token string
+	calls int
+}
+
+func (s *staticTokenSource) Token() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls++
+
+	return s.token, nil
+}
+
+// subjectToken returns a valid JWT carrying subject as its "sub" claim.
+func subjectToken(t *testing.T, subject string) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": subject,
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("signing test token: %s", err)
+	}
+
+	return token
+}
+
+func TestWithTokenSource(t *testing.T) {
+	source := &staticTokenSource{token: subjectToken(t, "sourced")}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer "+source.token {
+			t.Errorf("unexpected Authorization header: %s", got)
+		}
+
+		fmt.Fprint(w, `{"meta":{"page":{"number":1,"size":10,"total":0}},"data":[]}`)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, "", WithTokenSource(source))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := c.ListRuntimeGroups(context.Background(), ListOptions{}); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	if source.calls != 1 {
+		t.Fatalf("expected the sourced token to be cached, got %d calls", source.calls)
+	}
+}
+
+func TestConcurrentTokenRotation(t *testing.T) {
+	tokens := make([]string, 5)
+	valid := make(map[string]bool, len(tokens))
+	for i := range tokens {
+		tokens[i] = subjectToken(t, fmt.Sprintf("rotation-%d", i))
+		valid["Bearer "+tokens[i]] = true
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); !valid[got] {
+			t.Errorf("unexpected Authorization header: %s", got)
+		}
+
+		fmt.Fprint(w, `{"meta":{"page":{"number":1,"size":10,"total":0}},"data":[]}`)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, tokens[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 10; j++ {
+				if _, err := c.ListRuntimeGroups(context.Background(), ListOptions{}); err != nil {
+					t.Errorf("unexpected error: %s", err)
+				}
+			}
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 50; i++ {
+			if err := c.SetToken(tokens[i%len(tokens)]); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		}
+	}()
+
+	wg.Wait()
+}
+
+func TestUnauthorizedRefreshesToken(t *testing.T) {
+	revoked := subjectToken(t, "revoked")
+	source := &staticTokenSource{token: subjectToken(t, "rotated")}
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		if got := r.Header.Get("Authorization"); got != "Bearer "+source.token {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		// The body is replayed on the retry.
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"name":"test"`) {
+			t.Errorf("unexpected body: %s", body)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":"rg-1","name":"test"}`)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, revoked, WithTokenSource(source))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if _, err := c.CreateRuntimeGroup(CreateRuntimeGroupRequest{Name: "test"}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if requests != 2 || source.calls != 1 {
+		t.Fatalf("expected 2 requests and 1 sourced token, got %d and %d", requests, source.calls)
+	}
+}
+
+func TestUnauthorizedRefreshesTokenOnce(t *testing.T) {
+	source := &staticTokenSource{token: subjectToken(t, "rejected")}
+
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, "", WithTokenSource(source))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	_, err = c.GetRuntimeGroup(context.Background(), "rg-1")
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+
+	if requests != 2 {
+		t.Fatalf("expected a single refresh-retry, got %d requests", requests)
+	}
+}
+
+func TestUnauthorizedWithoutTokenSource(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	_, err = c.GetRuntimeGroup(context.Background(), "rg-1")
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+
+	if requests != 1 {
+		t.Fatalf("expected no retry without a token source, got %d requests", requests)
+	}
+}
+
+func TestIdentity(t *testing.T) {
+	tests := map[string]struct {
+		claims jwt.MapClaims
+		want   Identity
+	}{
+		"scope claim": {
+			claims: jwt.MapClaims{"sub": "user-1", "org_id": "org-1", "scope": "read write"},
+			want:   Identity{Subject: "user-1", OrganizationID: "org-1", Scopes: []string{"read", "write"}},
+		},
+		"scp claim": {
+			claims: jwt.MapClaims{"sub": "user-1", "scp": []string{"read"}},
+			want:   Identity{Subject: "user-1", Scopes: []string{"read"}},
+		},
+		"missing claims": {
+			claims: jwt.MapClaims{},
+		},
+		"non-string claims": {
+			claims: jwt.MapClaims{"sub": 42, "org_id": true},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tt.claims["exp"] = time.Now().Add(time.Hour).Unix()
+			token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, tt.claims).SignedString([]byte("secret"))
+			if err != nil {
+				t.Fatalf("signing test token: %s", err)
+			}
+
+			c, err := New("https://example.com", token)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			got, err := c.Identity()
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Fatalf("expected %+v, got %+v", tt.want, *got)
+			}
+		})
+	}
+}
+
+func TestValidateToken(t *testing.T) {
+	valid := testToken(t)
+
+	expired, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "test",
+		"exp": time.Now().Add(-time.Hour).Unix(),
+	}).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("signing test token: %s", err)
+	}
+
+	tests := map[string]struct {
+		token   string
+		status  int
+		wantErr error
+	}{
+		"valid":        {token: valid, status: http.StatusOK},
+		"expired":      {token: expired, status: http.StatusUnauthorized, wantErr: ErrUnauthorized},
+		"unauthorized": {token: valid, status: http.StatusForbidden, wantErr: ErrUnauthorized},
+		"server error": {token: valid, status: http.StatusInternalServerError},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/runtime-groups" || r.URL.Query().Get("page[size]") != "1" {
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+				}
+
+				if r.Header.Get("Authorization") != "Bearer "+tt.token {
+					t.Errorf("expected the token in the Authorization header, got %q", r.Header.Get("Authorization"))
+				}
+
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, `{"meta":{"page":{"number":1,"size":1,"total":0}},"data":[]}`)
+			}))
+			defer server.Close()
+
+			// The expired token passes New, the API rejects it.
+			c, err := New(server.URL, tt.token, WithSkipTokenValidation(true))
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			err = c.ValidateToken(context.Background())
+			switch {
+			case tt.status == http.StatusOK:
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) || !strings.Contains(err.Error(), "rejected the token") {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+			default:
+				if err == nil || errors.Is(err, ErrUnauthorized) {
+					t.Fatalf("expected a non-auth error, got %v", err)
+				}
+			}
+		})
+	}
+}
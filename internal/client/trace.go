@@ -0,0 +1,101 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// defaultMaxBodyLogBytes is the number of bytes of the bodies logged by default.
+const defaultMaxBodyLogBytes = 4096
+
+// maxSecretLength is the number of bytes read past the logged start of a body,
+// so that a secret straddling the limit is still redacted whole.
+const maxSecretLength = 2048
+
+// traceRequest logs the method, URL and body of req at trace level. Headers
+// are left out as they carry the credentials.
+func (c *Client) traceRequest(req *http.Request) {
+	fields := map[string]interface{}{
+		"method": req.Method,
+		"url":    req.URL.String(),
+	}
+
+	if c.logBodies() && req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(io.LimitReader(body, int64(c.maxBodyLogBytes+maxSecretLength)))
+			body.Close()
+			fields["body"] = truncateBody(data, c.maxBodyLogBytes)
+		}
+	}
+
+	tflog.Trace(req.Context(), "Sending HTTP request", fields)
+}
+
+// traceResponse logs the status and body of resp at trace level. Only the
+// logged start of the body is read, and put back in front of the rest so that
+// the caller can still decode it.
+func (c *Client) traceResponse(req *http.Request, resp *http.Response) error {
+	fields := map[string]interface{}{
+		"method": req.Method,
+		"url":    req.URL.String(),
+		"status": resp.StatusCode,
+	}
+
+	if c.logBodies() {
+		data, err := io.ReadAll(io.LimitReader(resp.Body, int64(c.maxBodyLogBytes+maxSecretLength)))
+		if err != nil {
+			resp.Body.Close()
+			return err
+		}
+
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(data), resp.Body), resp.Body}
+		fields["body"] = truncateBody(data, c.maxBodyLogBytes)
+	}
+
+	tflog.Trace(req.Context(), "Received HTTP response", fields)
+
+	return nil
+}
+
+// logBodies reports whether the bodies are logged, i.e. they are not omitted
+// with WithMaxBodyLogBytes and Terraform shows the trace logs of the provider.
+func (c *Client) logBodies() bool {
+	return c.maxBodyLogBytes > 0 && traceLogging()
+}
+
+// traceLogging reports whether Terraform shows the trace logs of the provider.
+// Like Terraform, it reads the level from TF_LOG_PROVIDER, falling back to
+// TF_LOG, where any value other than a lower level means trace.
+func traceLogging() bool {
+	level := os.Getenv("TF_LOG_PROVIDER")
+	if level == "" {
+		level = os.Getenv("TF_LOG")
+	}
+
+	switch strings.ToUpper(level) {
+	case "", "OFF", "ERROR", "WARN", "INFO", "DEBUG":
+		return false
+	}
+
+	return true
+}
+
+// truncateBody returns the first n bytes of body with the secrets it contains
+// redacted, followed by "..." when body is longer.
+func truncateBody(body []byte, n int) string {
+	// Redact before truncating so that a secret cut in half is still redacted.
+	redacted := secretPattern.ReplaceAllString(string(body), "[REDACTED]")
+	if len(redacted) > n {
+		redacted = strings.ToValidUTF8(redacted[:n], "") + "..."
+	}
+
+	return redacted
+}
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+)
+
+func TestTraceLogging(t *testing.T) {
+	token := testToken(t)
+	// The token straddles the limit, a naive truncation would log its start.
+	description := strings.Repeat("a", 40) + token
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"id": "42", "name": "test", "description": %q}`, description)
+	}))
+	defer server.Close()
+
+	testCases := map[string]struct {
+		opts    []Option
+		level   string
+		maxSize int
+	}{
+		"default": {
+			level:   "TRACE",
+			maxSize: defaultMaxBodyLogBytes,
+		},
+		"truncated": {
+			opts:    []Option{WithMaxBodyLogBytes(64)},
+			level:   "TRACE",
+			maxSize: 64,
+		},
+		"omitted": {
+			opts:  []Option{WithMaxBodyLogBytes(0)},
+			level: "TRACE",
+		},
+		"not shown by terraform": {
+			level: "DEBUG",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("TF_LOG_PROVIDER", tc.level)
+
+			var output bytes.Buffer
+			ctx := tflogtest.RootLogger(context.Background(), &output)
+
+			c, err := New(server.URL, token, tc.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if _, err := c.CreateRuntimeGroupWithContext(ctx, CreateRuntimeGroupRequest{Name: "test", Description: description}); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			entries, err := tflogtest.MultilineJSONDecode(&output)
+			if err != nil {
+				t.Fatalf("decoding logs: %s", err)
+			}
+
+			if len(entries) != 2 {
+				t.Fatalf("expected a request and a response entry, got %v", entries)
+			}
+
+			for _, entry := range entries {
+				body, ok := entry["body"].(string)
+				if tc.maxSize == 0 {
+					if ok {
+						t.Errorf("expected no body, got %q", body)
+					}
+					continue
+				}
+
+				if len(body) > tc.maxSize+len("...") {
+					t.Errorf("expected a body of at most %d bytes, got %d", tc.maxSize, len(body))
+				}
+
+				if tc.maxSize < 100 && !strings.HasSuffix(body, "...") {
+					t.Errorf("expected a truncated body, got %q", body)
+				}
+
+				if strings.Contains(body, "eyJ") {
+					t.Errorf("expected the token to be redacted, got %q", body)
+				}
+			}
+		})
+	}
+}
+
+func TestTraceLoggingLargeBody(t *testing.T) {
+	t.Setenv("TF_LOG_PROVIDER", "TRACE")
+
+	description := strings.Repeat("a", 4*(defaultMaxBodyLogBytes+maxSecretLength))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"id": "42", "name": "test", "description": %q}`, description)
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Only the start of the body is logged, the caller still reads all of it.
+	group, err := c.GetRuntimeGroup(ctx, "42")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if group.Description != description {
+		t.Fatalf("expected the description of %d bytes, got %d", len(description), len(group.Description))
+	}
+}
+
+func TestTraceLoggingLevel(t *testing.T) {
+	tests := map[string]struct {
+		provider string
+		tfLog    string
+		want     bool
+	}{
+		"unset":                    {},
+		"trace":                    {tfLog: "TRACE", want: true},
+		"json":                     {tfLog: "JSON", want: true},
+		"debug":                    {tfLog: "debug"},
+		"provider level overrides": {provider: "INFO", tfLog: "TRACE"},
+		"provider trace":           {provider: "trace", tfLog: "OFF", want: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("TF_LOG_PROVIDER", tt.provider)
+			t.Setenv("TF_LOG", tt.tfLog)
+
+			if got := traceLogging(); got != tt.want {
+				t.Fatalf("expected %t, got %t", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const (
+	// runtimeGroupTransferEndpoint is the action of a runtime group moving it to
+	// another organization.
+	runtimeGroupTransferEndpoint = "transfer"
+
+	// transferRuntimeGroupMethod is the HTTP method for transferring a runtime group.
+	transferRuntimeGroupMethod = http.MethodPost
+)
+
+// transferRuntimeGroupRequest represents the request body for transferring a runtime group.
+type transferRuntimeGroupRequest struct {
+	OrganizationID string `json:"organization_id"`
+}
+
+// TransferRuntimeGroup sends a POST request to move the runtime group with the
+// given id to the organization with id targetOrgID. The runtime group is no
+// longer visible to the organization of the token afterwards. An error matching
+// ErrNotSupported is returned when the API does not expose the action.
+func (c *Client) TransferRuntimeGroup(ctx context.Context, id, targetOrgID string) error {
+	defer c.locks.lock(id)()
+
+	c.cache.invalidate(id)
+	defer c.cache.invalidate(id)
+
+	if targetOrgID == "" {
+		return c.wrap("validating request body", fmt.Errorf("target organization id is required"))
+	}
+
+	requestBodyBytes, err := json.Marshal(transferRuntimeGroupRequest{OrganizationID: targetOrgID})
+	if err != nil {
+		return c.wrap("serializing request body", err)
+	}
+
+	endpoint, err := c.endpoint(OperationTransferRuntimeGroup, runtimeGroupEndpoint, url.PathEscape(id), runtimeGroupTransferEndpoint)
+	if err != nil {
+		return c.wrap("joining base URL and endpoint", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, transferRuntimeGroupMethod, endpoint, bytes.NewBuffer(requestBodyBytes))
+	if err != nil {
+		return c.wrap("creating HTTP request", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return c.wrap("making HTTP request", err)
+	}
+	defer resp.Body.Close()
+
+	// Check the HTTP response status code.
+	if err := c.checkResponse(resp); err != nil {
+		if errors.Is(err, ErrNotSupported) {
+			return c.wrap("checking status code", fmt.Errorf("the API does not support transferring runtime groups between organizations: %w", err))
+		}
+		return c.wrap("checking status code", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTransferRuntimeGroup(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/runtime-groups/rg-1/transfer" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("reading request body: %s", err)
+		}
+
+		if want := `{"organization_id":"org-2"}`; string(body) != want {
+			t.Errorf("expected body %s, got %s", want, body)
+		}
+
+		if got := r.Header.Get("Content-Type"); got != "application/json" {
+			t.Errorf("expected JSON content type, got %q", got)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if err := c.TransferRuntimeGroup(context.Background(), "rg-1", "org-2"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestTransferRuntimeGroupErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotImplemented)
+	}))
+	defer server.Close()
+
+	c, err := New(server.URL, testToken(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	err = c.TransferRuntimeGroup(context.Background(), "rg-1", "org-2")
+	if !errors.Is(err, ErrNotSupported) || !strings.Contains(err.Error(), "does not support transferring") {
+		t.Fatalf("expected a not supported error, got %v", err)
+	}
+
+	if err := c.TransferRuntimeGroup(context.Background(), "rg-1", ""); err == nil {
+		t.Fatal("expected an error for an empty organization id")
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"sync"
+
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/client"
+)
+
+// clientRef points to the Konnect client of the latest provider configuration.
+// It is the provider data of the resources and data sources, so that the
+// client rebuilt by a reconfiguration, e.g. with a rotated token, is used
+// without configuring them again.
+type clientRef struct {
+	mu     sync.RWMutex
+	client *client.Client
+}
+
+// Get returns the client of the latest provider configuration.
+func (r *clientRef) Get() *client.Client {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.client
+}
+
+// set replaces the client, e.g. on reconfiguration.
+func (r *clientRef) set(c *client.Client) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.client = c
+}
@@ -0,0 +1,66 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/client"
+)
+
+// diagFromAPIError returns the diagnostic of err, which failed op, e.g.
+// "create runtime group". API errors are titled after their status code and
+// carry the request id and the details of the error object, other errors are
+// reported as client errors.
+func diagFromAPIError(op string, err error) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) {
+		diags.AddError("Client Error", fmt.Sprintf("Unable to %s, got error: %s", op, err))
+		return diags
+	}
+
+	var summary, hint string
+	switch {
+	case apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden:
+		summary = "Konnect Authentication Error"
+		hint = " Check the token is valid and allowed to manage runtime groups."
+	case apiErr.StatusCode == http.StatusNotFound:
+		summary = "Runtime Group Not Found"
+	case apiErr.StatusCode == http.StatusConflict:
+		summary = "Runtime Group Conflict"
+	case apiErr.StatusCode == http.StatusTooManyRequests:
+		summary = "Konnect Rate Limit Exceeded"
+		hint = " Retry later or lower the parallelism of Terraform."
+	default:
+		summary = "Konnect API Error"
+	}
+
+	detail := fmt.Sprintf("Unable to %s, got error: %s.%s", op, err, hint)
+	if apiErr.Instance != "" {
+		detail += "\nInstance: " + apiErr.Instance
+	}
+	if apiErr.RequestID != "" {
+		detail += "\nRequest ID: " + apiErr.RequestID
+	}
+
+	diags.AddError(summary, detail)
+
+	return diags
+}
+
+// warningFromAPIError is diagFromAPIError for the failures that must not fail
+// the operation, reported as a warning.
+func warningFromAPIError(op string, err error) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, d := range diagFromAPIError(op, err) {
+		diags.AddWarning(d.Summary(), d.Detail())
+	}
+
+	return diags
+}
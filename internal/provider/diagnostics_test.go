@@ -0,0 +1,86 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/client"
+)
+
+func TestWarningFromAPIError(t *testing.T) {
+	diags := warningFromAPIError("read runtime group status", fmt.Errorf("|client error: checking status code -> %w", &client.APIError{
+		StatusCode: http.StatusForbidden,
+		RequestID:  "req-1",
+	}))
+
+	if len(diags) != 1 || diags.HasError() || diags.WarningsCount() != 1 {
+		t.Fatalf("expected a single warning, got %v", diags)
+	}
+
+	if summary := diags[0].Summary(); summary != "Konnect Authentication Error" {
+		t.Fatalf("expected the categorized summary, got %q", summary)
+	}
+
+	if detail := diags[0].Detail(); !strings.Contains(detail, "Request ID: req-1") {
+		t.Fatalf("expected the request id in the detail, got %q", detail)
+	}
+}
+
+func TestDiagFromAPIError(t *testing.T) {
+	apiErr := func(status int) error {
+		return fmt.Errorf("|client error: checking status code -> %w", &client.APIError{
+			StatusCode: status,
+			Title:      http.StatusText(status),
+			Detail:     "details of the error",
+			RequestID:  "req-1",
+		})
+	}
+
+	tests := map[string]struct {
+		err         error
+		wantSummary string
+	}{
+		"unauthorized": {err: apiErr(http.StatusUnauthorized), wantSummary: "Konnect Authentication Error"},
+		"forbidden":    {err: apiErr(http.StatusForbidden), wantSummary: "Konnect Authentication Error"},
+		"not found":    {err: apiErr(http.StatusNotFound), wantSummary: "Runtime Group Not Found"},
+		"conflict":     {err: apiErr(http.StatusConflict), wantSummary: "Runtime Group Conflict"},
+		"rate limited": {err: apiErr(http.StatusTooManyRequests), wantSummary: "Konnect Rate Limit Exceeded"},
+		"generic":      {err: apiErr(http.StatusInternalServerError), wantSummary: "Konnect API Error"},
+		"client":       {err: errors.New("connection refused"), wantSummary: "Client Error"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			diags := diagFromAPIError("read runtime group", tt.err)
+
+			if len(diags) != 1 || !diags.HasError() {
+				t.Fatalf("expected a single error, got %v", diags)
+			}
+
+			if summary := diags[0].Summary(); summary != tt.wantSummary {
+				t.Fatalf("expected summary %q, got %q", tt.wantSummary, summary)
+			}
+
+			detail := diags[0].Detail()
+			if !strings.HasPrefix(detail, "Unable to read runtime group, got error: ") {
+				t.Fatalf("expected the operation in the detail, got %q", detail)
+			}
+
+			if tt.wantSummary == "Client Error" {
+				return
+			}
+
+			for _, want := range []string{"details of the error", "Request ID: req-1"} {
+				if !strings.Contains(detail, want) {
+					t.Fatalf("expected detail to contain %q, got %q", want, detail)
+				}
+			}
+		})
+	}
+}
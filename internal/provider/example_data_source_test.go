@@ -4,11 +4,34 @@
 package provider
 
 import (
+	"context"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
+func TestExampleDataSourceConfigure(t *testing.T) {
+	providerResp := configureProvider(t, map[string]tftypes.Value{
+		"token": tftypes.NewValue(tftypes.String, testToken(t)),
+	})
+	if providerResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", providerResp.Diagnostics)
+	}
+
+	d := &ExampleDataSource{}
+	var resp datasource.ConfigureResponse
+	d.Configure(context.Background(), datasource.ConfigureRequest{ProviderData: providerResp.DataSourceData}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if d.clients == nil || d.clients.Get() == nil {
+		t.Fatal("expected the data source to hold the provider client")
+	}
+}
+
 func TestAccExampleDataSource(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
@@ -4,12 +4,35 @@
 package provider
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
+	fwresource "github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
 )
 
+func TestExampleResourceConfigure(t *testing.T) {
+	providerResp := configureProvider(t, map[string]tftypes.Value{
+		"token": tftypes.NewValue(tftypes.String, testToken(t)),
+	})
+	if providerResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", providerResp.Diagnostics)
+	}
+
+	r := &ExampleResource{}
+	var resp fwresource.ConfigureResponse
+	r.Configure(context.Background(), fwresource.ConfigureRequest{ProviderData: providerResp.ResourceData}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if r.clients == nil || r.clients.Get() == nil {
+		t.Fatal("expected the resource to hold the provider client")
+	}
+}
+
 func TestAccExampleResource(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { testAccPreCheck(t) },
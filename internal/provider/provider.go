@@ -5,13 +5,45 @@ package provider
 
 import (
 	"context"
-	"net/http"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/client"
+)
+
+const (
+	// defaultEndpoint is the Konnect API base URL used when none is configured.
+	defaultEndpoint = "https://global.api.konghq.com/v2"
+
+	// tokenEnvVar is the environment variable holding the Konnect API token.
+	tokenEnvVar = "KONNECT_TOKEN"
+
+	// skipCredentialsValidationEnvVar is the environment variable enabling
+	// skip_credentials_validation.
+	skipCredentialsValidationEnvVar = "KONNECT_SKIP_CREDENTIALS_VALIDATION"
+
+	// managedByLabelKey and managedByLabelValue form the label marking the
+	// runtime groups created by the provider, see managed_by_label.
+	managedByLabelKey   = "managed-by"
+	managedByLabelValue = "terraform"
+
+	// defaultReachabilityTimeout bounds the reachability check of the endpoint
+	// when reachability_timeout is not configured.
+	defaultReachabilityTimeout = 5 * time.Second
 )
 
 // Ensure ScaffoldingProvider satisfies various provider interfaces.
@@ -23,11 +55,25 @@ type ScaffoldingProvider struct {
 	// provider is built and ran locally, and "test" when running acceptance
 	// testing.
 	version string
+
+	// clients is shared with the resources and data sources, see clientRef.
+	clients *clientRef
 }
 
 // ScaffoldingProviderModel describes the provider data model.
 type ScaffoldingProviderModel struct {
-	Endpoint types.String `tfsdk:"endpoint"`
+	Endpoint                  types.String `tfsdk:"endpoint"`
+	Region                    types.String `tfsdk:"region"`
+	Environment               types.String `tfsdk:"environment"`
+	Token                     types.String `tfsdk:"token"`
+	DefaultLabels             types.Map    `tfsdk:"default_labels"`
+	DefaultClusterType        types.String `tfsdk:"default_cluster_type"`
+	ManagedByLabel            types.Bool   `tfsdk:"managed_by_label"`
+	SkipCredentialsValidation types.Bool   `tfsdk:"skip_credentials_validation"`
+	EndpointOverrides         types.Map    `tfsdk:"endpoint_overrides"`
+	ReachabilityTimeout       types.String `tfsdk:"reachability_timeout"`
+	GlobalRequestTimeout      types.String `tfsdk:"global_request_timeout"`
+	SerializeOperations       types.Bool   `tfsdk:"serialize_operations"`
 }
 
 func (p *ScaffoldingProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -39,8 +85,82 @@ func (p *ScaffoldingProvider) Schema(ctx context.Context, req provider.SchemaReq
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"endpoint": schema.StringAttribute{
-				MarkdownDescription: "Example provider attribute",
+				MarkdownDescription: "The Konnect API base URL. Overrides `region` and `environment`. Defaults to `" + defaultEndpoint + "`.",
+				Optional:            true,
+			},
+			"region": schema.StringAttribute{
+				MarkdownDescription: "The Konnect region selecting the API base URL together with `environment`, one of " +
+					quoteList(konnectRegions()) + ". Defaults to `" + defaultRegion + "`.",
+				Optional:   true,
+				Validators: []validator.String{stringvalidator.OneOf(konnectRegions()...)},
+			},
+			"environment": schema.StringAttribute{
+				MarkdownDescription: "The Konnect environment selecting the API base URL together with `region`, one of " +
+					quoteList(konnectEnvironments()) + ". Defaults to `" + defaultEnvironment + "`.",
+				Optional:   true,
+				Validators: []validator.String{stringvalidator.OneOf(konnectEnvironments()...)},
+			},
+			"token": schema.StringAttribute{
+				MarkdownDescription: "The Konnect API bearer token. May also be set with the `" + tokenEnvVar + "` environment variable.",
 				Optional:            true,
+				Sensitive:           true,
+			},
+			"default_labels": schema.MapAttribute{
+				MarkdownDescription: "Labels merged into the labels of every runtime group created by the provider. " +
+					"Labels set on the resource win on key conflicts. Values may reference the runtime group as `$name` and " +
+					"`$cluster_type`, e.g. `{ owner = \"$name-team\" }`, and `$$` stands for a literal `$`.",
+				Optional: true,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(labelKeyValidator{}),
+					mapvalidator.ValueStringsAre(labelTemplateValidator{}),
+				},
+				ElementType: types.StringType,
+			},
+			"managed_by_label": schema.BoolAttribute{
+				MarkdownDescription: "Whether to add the `" + managedByLabelKey + ": " + managedByLabelValue + "` label to every runtime group " +
+					"created by the provider, to tell them apart in the Konnect UI. Like `default_labels`, the label is not reported as drift " +
+					"and labels set in `default_labels` or on the resource win. Defaults to `false`.",
+				Optional: true,
+			},
+			"default_cluster_type": schema.StringAttribute{
+				MarkdownDescription: "The cluster type of the runtime groups created without `cluster_type`, one of " +
+					quoteList(client.ClusterTypes()) + ". The `cluster_type` of the resource wins.",
+				Optional:   true,
+				Validators: []validator.String{stringvalidator.OneOf(client.ClusterTypes()...)},
+			},
+			"skip_credentials_validation": schema.BoolAttribute{
+				MarkdownDescription: "Skip the validation of the token, e.g. to run `terraform plan` in CI without credentials. " +
+					"Operations calling the API still require a token. May also be set with the `" + skipCredentialsValidationEnvVar + "` environment variable.",
+				Optional: true,
+			},
+			"endpoint_overrides": schema.MapAttribute{
+				MarkdownDescription: "Base URLs replacing `endpoint` for individual operations, e.g. to test against mock servers. " +
+					"Keys are operation names, one of " + quoteList(client.Operations()) + ".",
+				Optional: true,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.OneOf(client.Operations()...)),
+					mapvalidator.ValueStringsAre(endpointValidator{}),
+				},
+				ElementType: types.StringType,
+			},
+			"reachability_timeout": schema.StringAttribute{
+				MarkdownDescription: "How long to wait for a TCP connection to the host of the API base URL when the provider is configured, " +
+					"then for the API to accept the token, so that misconfigured URLs and tokens fail early. " +
+					"Skipped with `skip_credentials_validation`, disabled with `0s`. " +
+					"A Go duration, defaults to `" + defaultReachabilityTimeout.String() + "`.",
+				Optional: true,
+			},
+			"global_request_timeout": schema.StringAttribute{
+				MarkdownDescription: "Upper bound of every single request to the API, whatever the operation. " +
+					"It does not replace the `timeouts` block of the resources: the smaller of the two wins, " +
+					"so an operation retrying several requests may still last up to its own timeout. " +
+					"A Go duration, no bound by default.",
+				Optional: true,
+			},
+			"serialize_operations": schema.BoolAttribute{
+				MarkdownDescription: "Whether to run the changes to the same runtime group one at a time, for APIs mishandling concurrent " +
+					"changes of a runtime group. Changes to different runtime groups still run in parallel. Defaults to `false`.",
+				Optional: true,
 			},
 		},
 	}
@@ -55,24 +175,224 @@ func (p *ScaffoldingProvider) Configure(ctx context.Context, req provider.Config
 		return
 	}
 
-	// Configuration values are now available.
-	// if data.Endpoint.IsNull() { /* ... */ }
+	region := defaultRegion
+	if !data.Region.IsNull() {
+		region = data.Region.ValueString()
+	}
+
+	environment := defaultEnvironment
+	if !data.Environment.IsNull() {
+		environment = data.Environment.ValueString()
+	}
+
+	// An explicit endpoint wins, the region is not validated against it.
+	endpoint := data.Endpoint.ValueString()
+	if data.Endpoint.IsNull() {
+		var err error
+		endpoint, err = konnectEndpoint(region, environment)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("region"),
+				"Unsupported Konnect Region",
+				fmt.Sprintf("Unable to select the Konnect API base URL: %s. Set endpoint to use another base URL.", err),
+			)
+
+			return
+		}
+	}
+
+	token := os.Getenv(tokenEnvVar)
+	if !data.Token.IsNull() {
+		token = data.Token.ValueString()
+	}
+
+	skipCredentialsValidation, _ := strconv.ParseBool(os.Getenv(skipCredentialsValidationEnvVar))
+	if !data.SkipCredentialsValidation.IsNull() {
+		skipCredentialsValidation = data.SkipCredentialsValidation.ValueBool()
+	}
+
+	if token == "" && !skipCredentialsValidation {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("token"),
+			"Missing Konnect API Token",
+			"The provider cannot create the Konnect API client as there is a missing or empty value for the Konnect API token. "+
+				"Set the token value in the configuration or use the "+tokenEnvVar+" environment variable.",
+		)
+
+		return
+	}
+
+	reachabilityTimeout := defaultReachabilityTimeout
+	if !data.ReachabilityTimeout.IsNull() {
+		var err error
+		reachabilityTimeout, err = time.ParseDuration(data.ReachabilityTimeout.ValueString())
+		if err != nil || reachabilityTimeout < 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("reachability_timeout"),
+				"Invalid Reachability Timeout",
+				fmt.Sprintf("Reachability timeout must be a non-negative Go duration, e.g. 5s, got: %q.", data.ReachabilityTimeout.ValueString()),
+			)
+
+			return
+		}
+	}
+
+	if !skipCredentialsValidation && reachabilityTimeout > 0 {
+		if err := checkReachable(ctx, endpoint, reachabilityTimeout); err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("endpoint"),
+				"Unreachable Konnect API",
+				fmt.Sprintf("Unable to connect to the Konnect API at %s within %s, got error: %s. "+
+					"Check the endpoint, region and environment, or raise reachability_timeout.", endpoint, reachabilityTimeout, err),
+			)
+
+			return
+		}
+	}
+
+	// A new client is configured for every plan or apply, so the read cache
+	// only lives for a single operation.
+	opts := []client.Option{
+		client.WithSkipTokenValidation(skipCredentialsValidation),
+		client.WithReadCache(true),
+	}
+
+	if !data.GlobalRequestTimeout.IsNull() {
+		requestTimeout, err := time.ParseDuration(data.GlobalRequestTimeout.ValueString())
+		if err != nil || requestTimeout < 0 {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("global_request_timeout"),
+				"Invalid Global Request Timeout",
+				fmt.Sprintf("Global request timeout must be a non-negative Go duration, e.g. 30s, got: %q.", data.GlobalRequestTimeout.ValueString()),
+			)
+
+			return
+		}
+
+		opts = append(opts, client.WithRequestTimeout(requestTimeout))
+	}
+
+	if data.SerializeOperations.ValueBool() {
+		opts = append(opts, client.WithPerResourceSerialization(true))
+	}
+
+	defaultLabels := make(map[string]string)
+	if data.ManagedByLabel.ValueBool() {
+		defaultLabels[managedByLabelKey] = managedByLabelValue
+	}
+
+	if !data.DefaultLabels.IsNull() {
+		configured := make(map[string]string)
+		resp.Diagnostics.Append(data.DefaultLabels.ElementsAs(ctx, &configured, false)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		for k, v := range configured {
+			defaultLabels[k] = v
+		}
+	}
+
+	if len(defaultLabels) > 0 {
+		opts = append(opts, client.WithDefaultLabels(defaultLabels))
+	}
+
+	if !data.DefaultClusterType.IsNull() {
+		opts = append(opts, client.WithDefaultClusterType(client.ClusterType(data.DefaultClusterType.ValueString())))
+	}
+
+	if !data.EndpointOverrides.IsNull() {
+		endpointOverrides := make(map[string]string)
+		resp.Diagnostics.Append(data.EndpointOverrides.ElementsAs(ctx, &endpointOverrides, false)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		opts = append(opts, client.WithEndpointOverrides(endpointOverrides))
+	}
+
+	konnectClient, err := client.New(endpoint, token, opts...)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Unable to Create Konnect API Client",
+			"An unexpected error occurred when creating the Konnect API client: "+err.Error(),
+		)
+
+		return
+	}
+
+	// The token is checked against the API along with the reachability, an
+	// unreachable API is reported above.
+	if !skipCredentialsValidation && reachabilityTimeout > 0 {
+		validateCtx, cancel := context.WithTimeout(ctx, reachabilityTimeout)
+		err := konnectClient.ValidateToken(validateCtx)
+		cancel()
+
+		if errors.Is(err, client.ErrUnauthorized) {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("token"),
+				"Invalid Konnect API Token",
+				fmt.Sprintf("The Konnect API at %s rejected the token, got error: %s. "+
+					"Check the token, or set it with the %s environment variable.", endpoint, err, tokenEnvVar),
+			)
+
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.AddWarning(
+				"Unable to Validate Konnect API Token",
+				fmt.Sprintf("The token could not be checked against the Konnect API at %s, got error: %s", endpoint, err),
+			)
+		}
+	}
+
+	// Resources and data sources configured before a reconfiguration switch to
+	// the new client as well.
+	p.clients.set(konnectClient)
+
+	resp.DataSourceData = p.clients
+	resp.ResourceData = p.clients
+}
+
+// checkReachable opens, then closes, a TCP connection to the host of the
+// endpoint, resolving its name first.
+func checkReachable(ctx context.Context, endpoint string, timeout time.Duration) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = "443"
+		if u.Scheme == "http" {
+			port = "80"
+		}
+	}
+
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(u.Hostname(), port))
+	if err != nil {
+		return err
+	}
 
-	// Example client configuration for data sources and resources
-	client := http.DefaultClient
-	resp.DataSourceData = client
-	resp.ResourceData = client
+	return conn.Close()
 }
 
 func (p *ScaffoldingProvider) Resources(ctx context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
 		NewExampleResource,
+		NewRuntimeGroup,
 	}
 }
 
 func (p *ScaffoldingProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewExampleDataSource,
+		NewRuntimeGroupsDataSource,
+		NewWhoamiDataSource,
 	}
 }
 
@@ -80,6 +400,7 @@ func New(version string) func() provider.Provider {
 	return func() provider.Provider {
 		return &ScaffoldingProvider{
 			version: version,
+			clients: &clientRef{},
 		}
 	}
 }
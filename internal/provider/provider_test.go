@@ -4,10 +4,33 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/client"
 )
 
 // testAccProtoV6ProviderFactories are used to instantiate a provider during
@@ -22,4 +45,607 @@ func testAccPreCheck(t *testing.T) {
 	// You can add code here to run prior to any test case execution, for example assertions
 	// about the appropriate environment variables being set are common to see in a pre-check
 	// function.
+	if os.Getenv(tokenEnvVar) == "" {
+		t.Fatal(tokenEnvVar + " must be set for acceptance tests")
+	}
+}
+
+// testToken returns a JWT with valid claims accepted by client.New.
+func testToken(t *testing.T) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub": "test",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("signing test token: %s", err)
+	}
+
+	return token
+}
+
+// testClient returns a Konnect client talking to baseURL.
+func testClient(t *testing.T, baseURL string, opts ...client.Option) *client.Client {
+	t.Helper()
+
+	c, err := client.New(baseURL, testToken(t), opts...)
+	if err != nil {
+		t.Fatalf("creating client: %s", err)
+	}
+
+	return c
+}
+
+// testServer returns a stub server answering requests to the paths of routes
+// with their body, and with 404 Not Found otherwise.
+func testServer(t *testing.T, routes map[string]string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := routes[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// testObject builds a Terraform object value of the given schema type. Every
+// attribute missing from values is set to null.
+func testObject(t *testing.T, schemaType attr.Type, values map[string]tftypes.Value) tftypes.Value {
+	t.Helper()
+
+	objectType, ok := schemaType.TerraformType(context.Background()).(tftypes.Object)
+	if !ok {
+		t.Fatalf("expected an object type, got %T", schemaType)
+	}
+
+	attributes := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, attributeType := range objectType.AttributeTypes {
+		attributes[name] = tftypes.NewValue(attributeType, nil)
+	}
+
+	for name, value := range values {
+		if _, ok := attributes[name]; !ok {
+			t.Fatalf("unknown attribute %q", name)
+		}
+		attributes[name] = value
+	}
+
+	return tftypes.NewValue(objectType, attributes)
+}
+
+// copyValues returns a copy of values, never nil.
+func copyValues(values map[string]tftypes.Value) map[string]tftypes.Value {
+	copied := make(map[string]tftypes.Value, len(values)+1)
+	for name, value := range values {
+		copied[name] = value
+	}
+
+	return copied
+}
+
+// newClientRef returns a reference to c, as configured by the provider.
+func newClientRef(c *client.Client) *clientRef {
+	return &clientRef{client: c}
+}
+
+// configureProvider runs the provider Configure for the given config.
+func configureProvider(t *testing.T, config map[string]tftypes.Value) provider.ConfigureResponse {
+	t.Helper()
+
+	// Unit tests run offline: the reachability check is disabled unless the
+	// test configures it.
+	if _, ok := config["reachability_timeout"]; !ok {
+		config = copyValues(config)
+		config["reachability_timeout"] = tftypes.NewValue(tftypes.String, "0s")
+	}
+
+	ctx := context.Background()
+	p := New("test")()
+
+	var schemaResp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+
+	req := provider.ConfigureRequest{
+		Config: tfsdk.Config{
+			Schema: schemaResp.Schema,
+			Raw:    testObject(t, schemaResp.Schema.Type(), config),
+		},
+	}
+
+	var resp provider.ConfigureResponse
+	p.Configure(ctx, req, &resp)
+
+	return resp
+}
+
+func TestProviderConfigureMissingToken(t *testing.T) {
+	t.Setenv(tokenEnvVar, "")
+	t.Setenv(skipCredentialsValidationEnvVar, "")
+
+	resp := configureProvider(t, nil)
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error for a missing token")
+	}
+}
+
+func TestProviderConfigureSkipCredentialsValidation(t *testing.T) {
+	t.Setenv(tokenEnvVar, "")
+	t.Setenv(skipCredentialsValidationEnvVar, "")
+
+	resp := configureProvider(t, map[string]tftypes.Value{
+		"skip_credentials_validation": tftypes.NewValue(tftypes.Bool, true),
+		"token":                       tftypes.NewValue(tftypes.String, "not-a-jwt"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if _, ok := resp.ResourceData.(*clientRef); !ok {
+		t.Fatalf("expected a *clientRef, got %T", resp.ResourceData)
+	}
+}
+
+func TestProviderConfigureSkipCredentialsValidationEnvVar(t *testing.T) {
+	t.Setenv(tokenEnvVar, "")
+	t.Setenv(skipCredentialsValidationEnvVar, "true")
+
+	resp := configureProvider(t, nil)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+}
+
+func TestProviderConfigureEndpointOverrides(t *testing.T) {
+	t.Setenv(skipCredentialsValidationEnvVar, "")
+
+	mock := testServer(t, map[string]string{"/runtime-groups": `{"id":"rg-1","name":"test"}`})
+
+	resp := configureProvider(t, map[string]tftypes.Value{
+		"endpoint": tftypes.NewValue(tftypes.String, "https://unreachable.invalid"),
+		"token":    tftypes.NewValue(tftypes.String, testToken(t)),
+		"endpoint_overrides": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, map[string]tftypes.Value{
+			client.OperationCreateRuntimeGroup: tftypes.NewValue(tftypes.String, mock.URL),
+		}),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	clients, ok := resp.ResourceData.(*clientRef)
+	if !ok {
+		t.Fatalf("expected a *clientRef, got %T", resp.ResourceData)
+	}
+	c := clients.Get()
+
+	group, err := c.CreateRuntimeGroup(client.CreateRuntimeGroupRequest{Name: "test"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if group.ID != "rg-1" {
+		t.Fatalf("expected id rg-1, got %q", group.ID)
+	}
+}
+
+func TestProviderConfigureReachability(t *testing.T) {
+	t.Setenv(skipCredentialsValidationEnvVar, "")
+
+	reachable := testServer(t, nil)
+
+	// A closed listener leaves a port nothing listens on.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listening: %s", err)
+	}
+	unreachable := "http://" + listener.Addr().String()
+	listener.Close()
+
+	tests := map[string]struct {
+		endpoint   string
+		skip       bool
+		wantErr    bool
+		wantDetail string
+	}{
+		"reachable":   {endpoint: reachable.URL},
+		"unreachable": {endpoint: unreachable, wantErr: true, wantDetail: "Unable to connect to the Konnect API at " + unreachable},
+		"skipped":     {endpoint: unreachable, skip: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := configureProvider(t, map[string]tftypes.Value{
+				"endpoint":                    tftypes.NewValue(tftypes.String, tt.endpoint),
+				"token":                       tftypes.NewValue(tftypes.String, testToken(t)),
+				"skip_credentials_validation": tftypes.NewValue(tftypes.Bool, tt.skip),
+				"reachability_timeout":        tftypes.NewValue(tftypes.String, "2s"),
+			})
+
+			if resp.Diagnostics.HasError() != tt.wantErr {
+				t.Fatalf("expected error %t, got %v", tt.wantErr, resp.Diagnostics)
+			}
+
+			if tt.wantErr {
+				diagnostic := resp.Diagnostics.Errors()[0]
+				if diagnostic.Summary() != "Unreachable Konnect API" || !strings.Contains(diagnostic.Detail(), tt.wantDetail) {
+					t.Fatalf("unexpected diagnostic: %v", resp.Diagnostics)
+				}
+			}
+		})
+	}
+}
+
+func TestProviderConfigureValidatesToken(t *testing.T) {
+	t.Setenv(skipCredentialsValidationEnvVar, "")
+
+	tests := map[string]struct {
+		status      int
+		skip        bool
+		wantErr     bool
+		wantWarning bool
+	}{
+		"valid":        {status: http.StatusOK},
+		"rejected":     {status: http.StatusUnauthorized, wantErr: true},
+		"skipped":      {status: http.StatusUnauthorized, skip: true},
+		"server error": {status: http.StatusInternalServerError, wantWarning: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, `{"meta":{"page":{"number":1,"size":1,"total":0}},"data":[]}`)
+			}))
+			defer server.Close()
+
+			resp := configureProvider(t, map[string]tftypes.Value{
+				"endpoint":                    tftypes.NewValue(tftypes.String, server.URL),
+				"token":                       tftypes.NewValue(tftypes.String, testToken(t)),
+				"skip_credentials_validation": tftypes.NewValue(tftypes.Bool, tt.skip),
+				"reachability_timeout":        tftypes.NewValue(tftypes.String, "2s"),
+			})
+
+			if resp.Diagnostics.HasError() != tt.wantErr {
+				t.Fatalf("expected error %t, got %v", tt.wantErr, resp.Diagnostics)
+			}
+
+			if tt.wantErr && resp.Diagnostics.Errors()[0].Summary() != "Invalid Konnect API Token" {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			if got := len(resp.Diagnostics.Warnings()) > 0; got != tt.wantWarning {
+				t.Fatalf("expected warning %t, got %v", tt.wantWarning, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestProviderConfigureInvalidReachabilityTimeout(t *testing.T) {
+	resp := configureProvider(t, map[string]tftypes.Value{
+		"token":                tftypes.NewValue(tftypes.String, testToken(t)),
+		"reachability_timeout": tftypes.NewValue(tftypes.String, "soon"),
+	})
+
+	if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Invalid Reachability Timeout" {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+}
+
+func TestProviderConfigureGlobalRequestTimeout(t *testing.T) {
+	t.Setenv(skipCredentialsValidationEnvVar, "")
+
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(done)
+
+	resp := configureProvider(t, map[string]tftypes.Value{
+		"endpoint":               tftypes.NewValue(tftypes.String, server.URL),
+		"token":                  tftypes.NewValue(tftypes.String, testToken(t)),
+		"global_request_timeout": tftypes.NewValue(tftypes.String, "100ms"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	// The operation timeout is far longer than the global ceiling.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	start := time.Now()
+	c := resp.ResourceData.(*clientRef).Get()
+	if _, err := c.GetRuntimeGroup(ctx, "rg-1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("expected the request to be bounded by the global timeout, took %s", elapsed)
+	}
+}
+
+func TestProviderConfigureInvalidGlobalRequestTimeout(t *testing.T) {
+	resp := configureProvider(t, map[string]tftypes.Value{
+		"token":                  tftypes.NewValue(tftypes.String, testToken(t)),
+		"global_request_timeout": tftypes.NewValue(tftypes.String, "-1s"),
+	})
+
+	if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Invalid Global Request Timeout" {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+}
+
+func TestProviderConfigureManagedByLabel(t *testing.T) {
+	t.Setenv(skipCredentialsValidationEnvVar, "")
+
+	var created map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			var body client.CreateRuntimeGroupRequest
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("decoding request body: %s", err)
+			}
+			created = body.Labels
+		}
+
+		fmt.Fprint(w, `{"id":"rg-1","name":"test","labels":{"managed-by":"terraform","env":"dev"}}`)
+	}))
+	defer server.Close()
+
+	tests := map[string]struct {
+		managedBy bool
+		want      map[string]string
+	}{
+		"enabled":  {managedBy: true, want: map[string]string{"managed-by": "terraform", "env": "dev"}},
+		"disabled": {managedBy: false, want: map[string]string{"env": "dev"}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := configureProvider(t, map[string]tftypes.Value{
+				"endpoint":         tftypes.NewValue(tftypes.String, server.URL),
+				"token":            tftypes.NewValue(tftypes.String, testToken(t)),
+				"managed_by_label": tftypes.NewValue(tftypes.Bool, tt.managedBy),
+			})
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			c := resp.ResourceData.(*clientRef).Get()
+			if _, err := c.CreateRuntimeGroup(client.CreateRuntimeGroupRequest{Name: "test", Labels: map[string]string{"env": "dev"}}); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !reflect.DeepEqual(created, tt.want) {
+				t.Fatalf("expected labels %v, got %v", tt.want, created)
+			}
+		})
+	}
+}
+
+func TestProviderConfigureSerializeOperations(t *testing.T) {
+	t.Setenv(skipCredentialsValidationEnvVar, "")
+
+	var inFlight, maxInFlight int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			n := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+
+			for {
+				m := atomic.LoadInt32(&maxInFlight)
+				if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+					break
+				}
+			}
+
+			time.Sleep(50 * time.Millisecond)
+		}
+
+		fmt.Fprint(w, `{"id":"rg-1","name":"test"}`)
+	}))
+	defer server.Close()
+
+	resp := configureProvider(t, map[string]tftypes.Value{
+		"endpoint":             tftypes.NewValue(tftypes.String, server.URL),
+		"token":                tftypes.NewValue(tftypes.String, testToken(t)),
+		"serialize_operations": tftypes.NewValue(tftypes.Bool, true),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	c := resp.ResourceData.(*clientRef).Get()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if _, err := c.UpdateRuntimeGroup(context.Background(), "rg-1", client.UpdateRuntimeGroupRequest{Name: "test"}); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight != 1 {
+		t.Fatalf("expected the updates to run one at a time, got %d in flight", maxInFlight)
+	}
+}
+
+func TestProviderConfigureRegion(t *testing.T) {
+	t.Setenv(skipCredentialsValidationEnvVar, "")
+
+	tests := map[string]struct {
+		region      string
+		environment string
+		endpoint    string
+		want        string
+		wantErr     bool
+	}{
+		"default":                {want: defaultEndpoint},
+		"region":                 {region: "eu", want: "https://eu.api.konghq.com/v2"},
+		"region and environment": {region: "us", environment: "staging", want: "https://us.api.konghq.tech/v2"},
+		"environment":            {environment: "staging", want: "https://global.api.konghq.tech/v2"},
+		"unknown combination":    {region: "au", environment: "staging", wantErr: true},
+		"endpoint override":      {region: "au", environment: "staging", endpoint: "https://konnect.example.com", want: "https://konnect.example.com"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := map[string]tftypes.Value{
+				"token": tftypes.NewValue(tftypes.String, testToken(t)),
+			}
+			if tt.region != "" {
+				config["region"] = tftypes.NewValue(tftypes.String, tt.region)
+			}
+			if tt.environment != "" {
+				config["environment"] = tftypes.NewValue(tftypes.String, tt.environment)
+			}
+			if tt.endpoint != "" {
+				config["endpoint"] = tftypes.NewValue(tftypes.String, tt.endpoint)
+			}
+
+			resp := configureProvider(t, config)
+			if resp.Diagnostics.HasError() != tt.wantErr {
+				t.Fatalf("expected error %t, got %v", tt.wantErr, resp.Diagnostics)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			clients, ok := resp.ResourceData.(*clientRef)
+			if !ok {
+				t.Fatalf("expected a *clientRef, got %T", resp.ResourceData)
+			}
+			c := clients.Get()
+
+			if c.BaseUrl != tt.want {
+				t.Fatalf("expected base URL %s, got %s", tt.want, c.BaseUrl)
+			}
+		})
+	}
+}
+
+func TestEndpointValidator(t *testing.T) {
+	tests := map[string]struct {
+		value   string
+		wantErr bool
+	}{
+		"https":    {value: "https://example.com/v2"},
+		"http":     {value: "http://localhost:8080"},
+		"relative": {value: "/v2", wantErr: true},
+		"no host":  {value: "https://", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := validator.StringRequest{
+				Path:        path.Root("endpoint_overrides"),
+				ConfigValue: types.StringValue(tt.value),
+			}
+
+			var resp validator.StringResponse
+			endpointValidator{}.ValidateString(context.Background(), req, &resp)
+
+			if resp.Diagnostics.HasError() != tt.wantErr {
+				t.Fatalf("expected error %t, got %v", tt.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestLabelTemplateValidator(t *testing.T) {
+	tests := map[string]struct {
+		value   string
+		wantErr bool
+	}{
+		"literal":       {value: "platform"},
+		"name":          {value: "$name-team"},
+		"cluster type":  {value: "$cluster_type"},
+		"escaped":       {value: "$$5"},
+		"unknown token": {value: "$namespace", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := validator.StringRequest{
+				Path:        path.Root("default_labels").AtMapKey("owner"),
+				ConfigValue: types.StringValue(tt.value),
+			}
+
+			var resp validator.StringResponse
+			labelTemplateValidator{}.ValidateString(context.Background(), req, &resp)
+
+			if resp.Diagnostics.HasError() != tt.wantErr {
+				t.Fatalf("expected error %t, got %v", tt.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestProviderReconfigure(t *testing.T) {
+	t.Setenv(skipCredentialsValidationEnvVar, "")
+
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		fmt.Fprint(w, `{"id":"rg-1","name":"test"}`)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	p := New("test")()
+
+	var schemaResp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+
+	configure := func(token string) provider.ConfigureResponse {
+		req := provider.ConfigureRequest{
+			Config: tfsdk.Config{
+				Schema: schemaResp.Schema,
+				Raw: testObject(t, schemaResp.Schema.Type(), map[string]tftypes.Value{
+					"endpoint":                    tftypes.NewValue(tftypes.String, server.URL),
+					"token":                       tftypes.NewValue(tftypes.String, token),
+					"skip_credentials_validation": tftypes.NewValue(tftypes.Bool, true),
+				}),
+			},
+		}
+
+		var resp provider.ConfigureResponse
+		p.Configure(ctx, req, &resp)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+		}
+
+		return resp
+	}
+
+	// The resource is configured with the provider data of the first configuration only.
+	r := &RuntimeGroup{}
+	r.Configure(ctx, resource.ConfigureRequest{ProviderData: configure("first-token").ResourceData}, &resource.ConfigureResponse{})
+
+	configure("rotated-token")
+
+	if _, err := r.clients.Get().GetRuntimeGroup(ctx, "rg-1"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if authorization != "Bearer rotated-token" {
+		t.Fatalf("expected the rotated token to be used, got %q", authorization)
+	}
 }
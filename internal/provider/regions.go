@@ -0,0 +1,80 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	// defaultRegion and defaultEnvironment select defaultEndpoint.
+	defaultRegion      = "global"
+	defaultEnvironment = "prod"
+)
+
+// konnectEndpoints are the Konnect API base URLs by environment and region.
+var konnectEndpoints = map[string]map[string]string{
+	"prod": {
+		"global": defaultEndpoint,
+		"us":     "https://us.api.konghq.com/v2",
+		"eu":     "https://eu.api.konghq.com/v2",
+		"au":     "https://au.api.konghq.com/v2",
+	},
+	"staging": {
+		"global": "https://global.api.konghq.tech/v2",
+		"us":     "https://us.api.konghq.tech/v2",
+		"eu":     "https://eu.api.konghq.tech/v2",
+	},
+}
+
+// konnectRegions returns the sorted regions of every environment.
+func konnectRegions() []string {
+	seen := make(map[string]bool)
+	var regions []string
+	for _, endpoints := range konnectEndpoints {
+		for region := range endpoints {
+			if !seen[region] {
+				seen[region] = true
+				regions = append(regions, region)
+			}
+		}
+	}
+	sort.Strings(regions)
+
+	return regions
+}
+
+// konnectEnvironments returns the sorted environments.
+func konnectEnvironments() []string {
+	environments := make([]string, 0, len(konnectEndpoints))
+	for environment := range konnectEndpoints {
+		environments = append(environments, environment)
+	}
+	sort.Strings(environments)
+
+	return environments
+}
+
+// konnectEndpoint returns the base URL of region in environment, failing when
+// the environment does not serve the region.
+func konnectEndpoint(region, environment string) (string, error) {
+	endpoints, ok := konnectEndpoints[environment]
+	if !ok {
+		return "", fmt.Errorf("unknown environment %q, expected one of %s", environment, quoteList(konnectEnvironments()))
+	}
+
+	endpoint, ok := endpoints[region]
+	if !ok {
+		regions := make([]string, 0, len(endpoints))
+		for region := range endpoints {
+			regions = append(regions, region)
+		}
+		sort.Strings(regions)
+
+		return "", fmt.Errorf("region %q is not available in the %s environment, expected one of %s", region, environment, quoteList(regions))
+	}
+
+	return endpoint, nil
+}
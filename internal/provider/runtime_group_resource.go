@@ -5,21 +5,67 @@ package provider
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
 	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/client"
-	"regexp"
 )
 
 // Ensure provider defined types fully satisfy framework interfaces.
 var _ resource.Resource = &RuntimeGroup{}
 var _ resource.ResourceWithImportState = &RuntimeGroup{}
+var _ resource.ResourceWithValidateConfig = &RuntimeGroup{}
+var _ resource.ResourceWithModifyPlan = &RuntimeGroup{}
+var _ resource.ResourceWithUpgradeState = &RuntimeGroup{}
+
+// runtimeGroupIdPattern matches the UUIDs the API uses as runtime group ids.
+var runtimeGroupIdPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// runtimeGroupImportFormats are the import id formats accepted by ImportState.
+var runtimeGroupImportFormats = []string{
+	"`<id>`: the UUID of the runtime group.",
+	"`<name>`: the name of the runtime group.",
+	"`<cluster_type>/<name>`: the cluster type and the name of the runtime group, e.g. `CLUSTER_TYPE_HYBRID/default`.",
+}
+
+// Update strategies of the update_strategy attribute.
+const (
+	// updateStrategyMerge updates the runtime group with a PATCH request.
+	updateStrategyMerge = "merge"
+	// updateStrategyReplace replaces the runtime group with a PUT request.
+	updateStrategyReplace = "replace"
+)
+
+// Destroy actions of the on_destroy attribute.
+const (
+	destroyActionDelete  = "delete"
+	destroyActionArchive = "archive"
+)
+
+// runtimeGroupImportHelp renders the import id formats as a Markdown list.
+func runtimeGroupImportHelp() string {
+	return "- " + strings.Join(runtimeGroupImportFormats, "\n- ")
+}
 
 func NewRuntimeGroup() resource.Resource {
 	return &RuntimeGroup{}
@@ -27,80 +73,398 @@ func NewRuntimeGroup() resource.Resource {
 
 // RuntimeGroup defines the resource implementation.
 type RuntimeGroup struct {
-	client *client.Client
+	clients *clientRef
 }
 
 // RuntimeGroupModel describes the resource data model.
 type RuntimeGroupModel struct {
-	Id                   types.String `tfsdk:"id"`
-	Name                 types.String `tfsdk:"name"`
-	Description          types.String `tfsdk:"description"`
-	ClusterType          types.String `tfsdk:"cluster_type"`
-	Labels               types.Map    `tfsdk:"labels"`
-	ControlPlaneEndpoint types.String `tfsdk:"control_plane_endpoint"`
-	TelemetryEndpoint    types.String `tfsdk:"telemetry_endpoint"`
+	Id                   types.String   `tfsdk:"id"`
+	Name                 types.String   `tfsdk:"name"`
+	NamePrefix           types.String   `tfsdk:"name_prefix"`
+	Description          types.String   `tfsdk:"description"`
+	ClusterType          types.String   `tfsdk:"cluster_type"`
+	Labels               types.Map      `tfsdk:"labels"`
+	AllLabels            types.Map      `tfsdk:"all_labels"`
+	ControlPlaneEndpoint types.String   `tfsdk:"control_plane_endpoint"`
+	TelemetryEndpoint    types.String   `tfsdk:"telemetry_endpoint"`
+	IgnoreLabels         types.Set      `tfsdk:"ignore_labels"`
+	Status               types.String   `tfsdk:"status"`
+	RawResponse          types.String   `tfsdk:"raw_response"`
+	EntityVersion        types.Int64    `tfsdk:"entity_version"`
+	DeletionProtection   types.Bool     `tfsdk:"deletion_protection"`
+	OnDestroy            types.String   `tfsdk:"on_destroy"`
+	TransferOnDestroy    types.String   `tfsdk:"transfer_on_destroy"`
+	CheckNameUniqueness  types.Bool     `tfsdk:"check_name_uniqueness"`
+	WaitForReady         types.Bool     `tfsdk:"wait_for_ready"`
+	RotateOn             types.String   `tfsdk:"rotate_on"`
+	UpdateStrategy       types.String   `tfsdk:"update_strategy"`
+	Timeouts             timeouts.Value `tfsdk:"timeouts"`
 }
 
 func (r *RuntimeGroup) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
-	resp.TypeName = req.ProviderTypeName + "_example"
+	resp.TypeName = req.ProviderTypeName + "_runtime_group"
 }
 
 func (r *RuntimeGroup) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
-		MarkdownDescription: "Runtime group resource",
+		// Version 1 stores the label values unquoted, see UpgradeState.
+		Version:             1,
+		MarkdownDescription: "Runtime group resource.\n\nExisting runtime groups can be imported with any of the following ids:\n\n" + runtimeGroupImportHelp(),
 
 		Attributes: map[string]schema.Attribute{
 			"name": schema.StringAttribute{
-				MarkdownDescription: "The name of the runtime group.",
-				Required:            true,
+				MarkdownDescription: "The name of the runtime group. Generated from `name_prefix` when not set. Conflicts with `name_prefix`.",
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name_prefix": schema.StringAttribute{
+				MarkdownDescription: "Creates a unique name beginning with the specified prefix, e.g. for ephemeral runtime groups. " +
+					"It is truncated so that the name fits in " + strconv.Itoa(client.MaxNameLength) + " characters. Conflicts with `name`. Changing it forces a new resource.",
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
-			"Description": schema.StringAttribute{
+			"description": schema.StringAttribute{
 				MarkdownDescription: "The description of the runtime group in Konnect.",
 				Optional:            true,
 			},
 			"cluster_type": schema.StringAttribute{
-				MarkdownDescription: "The ClusterType value of the cluster associated with the Runtime Group.",
-				Optional:            true,
+				MarkdownDescription: "The ClusterType value of the cluster associated with the Runtime Group, one of " +
+					quoteList(client.ClusterTypes()) + ". Defaults to the `default_cluster_type` of the provider, if any, " +
+					"or else to the default of the API, read back from it. Changing it forces a new resource.",
+				Optional:   true,
+				Computed:   true,
+				Validators: []validator.String{stringvalidator.OneOf(client.ClusterTypes()...)},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"labels": schema.MapAttribute{
-				MarkdownDescription: "Labels to facilitate tagged search on runtime groups. Keys must be of length 1-63 characters, and cannot start with 'kong', 'konnect', 'mesh', 'kic'.",
+				MarkdownDescription: "Labels to facilitate tagged search on runtime groups, at most 50. Keys must be of length 1-63 characters, and cannot start with 'kong', 'konnect', 'mesh', 'kic'.",
 				Optional:            true,
-				Validators: []validator.Map{mapvalidator.KeysAre(stringvalidator.RegexMatches(regexp.MustCompile("^(?!kong|konnect|mesh|kic).{1,63}$"),
-					"Keys must be of length 1-63 characters, and cannot start with 'kong', 'konnect', 'mesh', 'kic'."))},
+				Validators:          []validator.Map{mapvalidator.KeysAre(labelKeyValidator{}), labelCountValidator{}},
+				ElementType:         types.StringType,
+			},
+			"all_labels": schema.MapAttribute{
+				MarkdownDescription: "Every label of the runtime group, including the provider `default_labels` and the labels " +
+					"added by Konnect itself under its reserved prefixes, e.g. `konnect.created_by`, which are left out of `labels`.",
+				Computed:    true,
+				ElementType: types.StringType,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"ignore_labels": schema.SetAttribute{
+				MarkdownDescription: "Label keys managed outside of Terraform, e.g. operational labels injected by Konnect. " +
+					"These keys are removed from the labels read back from the API, so external changes to them are not reported as drift, " +
+					"and their current values are sent back on update, so that changes to `labels` do not remove them.",
+				Optional:    true,
 				ElementType: types.StringType,
 			},
 			"control_plane_endpoint": schema.StringAttribute{
 				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
 			"telemetry_endpoint": schema.StringAttribute{
 				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"status": schema.StringAttribute{
+				MarkdownDescription: "The data plane connectivity status of the runtime group. Null when the API does not expose it.",
+				Computed:            true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"deletion_protection": schema.BoolAttribute{
+				MarkdownDescription: "Whether Terraform is prevented from deleting the runtime group, e.g. for production runtime groups. " +
+					"It must be set to `false` and applied before the runtime group can be destroyed. Defaults to `false`.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"on_destroy": schema.StringAttribute{
+				MarkdownDescription: "What destroying the resource does to the runtime group, `" + destroyActionDelete + "` or `" +
+					destroyActionArchive + "`. An archived runtime group is preserved by Konnect but no longer managed by Terraform, " +
+					"while a deleted one is gone for good. Defaults to `" + destroyActionDelete + "`.",
+				Optional:   true,
+				Computed:   true,
+				Default:    stringdefault.StaticString(destroyActionDelete),
+				Validators: []validator.String{stringvalidator.OneOf(destroyActionDelete, destroyActionArchive)},
+			},
+			"transfer_on_destroy": schema.StringAttribute{
+				MarkdownDescription: "The id of an organization the runtime group is transferred to when the resource is destroyed, " +
+					"rather than deleting it, e.g. to hand it over to another team. The transfer fails when the API does not support it. " +
+					"Conflicts with archiving on destroy.",
+				Optional: true,
+			},
+			"check_name_uniqueness": schema.BoolAttribute{
+				MarkdownDescription: "Whether to check that no runtime group has the same name before creating it, failing early " +
+					"rather than on the conflict reported by the API. It costs an extra API call. Defaults to `false`.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"wait_for_ready": schema.BoolAttribute{
+				MarkdownDescription: "Whether to wait on create until the data plane of the runtime group is connected, polling `status` " +
+					"until it is one of " + quoteList(readyStates) + " or the create timeout elapses, e.g. so that dependent " +
+					"resources can `depends_on` its readiness. Defaults to `false`.",
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+			"update_strategy": schema.StringAttribute{
+				MarkdownDescription: "How changes are sent to the API, `" + updateStrategyMerge + "` or `" + updateStrategyReplace + "`. " +
+					"`" + updateStrategyMerge + "` updates the configured fields with a PATCH request: the fields set outside of Terraform " +
+					"and not modeled by the provider are kept. `" + updateStrategyReplace + "` replaces the whole runtime group with a PUT " +
+					"request: those fields are reset, so changes made outside of Terraform do not survive an apply, except for the " +
+					"labels listed in `ignore_labels`. Defaults to `" + updateStrategyMerge + "`.",
+				Optional:   true,
+				Computed:   true,
+				Default:    stringdefault.StaticString(updateStrategyMerge),
+				Validators: []validator.String{stringvalidator.OneOf(updateStrategyMerge, updateStrategyReplace)},
+			},
+			"rotate_on": schema.StringAttribute{
+				MarkdownDescription: "An arbitrary value, e.g. a timestamp, whose changes rotate the data plane credentials of the runtime group, " +
+					"updating `control_plane_endpoint` and `telemetry_endpoint`. Credentials are not rotated on create nor when it is removed.",
+				Optional: true,
+			},
+			"entity_version": schema.Int64Attribute{
+				MarkdownDescription: "The version of the runtime group, incremented by the API on every change, e.g. for optimistic concurrency. " +
+					"Null when the API does not return it.",
+				Computed: true,
+			},
+			"raw_response": schema.StringAttribute{
+				MarkdownDescription: "The last JSON response of the API for the runtime group, including the fields the provider does not model yet. " +
+					"Use `jsondecode` to read it.",
+				Computed:  true,
+				Sensitive: true,
 			},
 			"id": schema.StringAttribute{
 				Computed:            true,
 				MarkdownDescription: "Service generated identifier for the Runtime Group.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+
+		Blocks: map[string]schema.Block{
+			"timeouts": timeouts.Block(ctx, timeouts.Opts{
+				Create: true,
+				Read:   true,
+				Update: true,
+				Delete: true,
+			}),
+		},
+	}
+}
+
+func (r *RuntimeGroup) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	priorSchema := runtimeGroupSchemaV0()
+
+	return map[int64]resource.StateUpgrader{
+		0: {
+			PriorSchema:   &priorSchema,
+			StateUpgrader: upgradeRuntimeGroupStateV0,
+		},
+	}
+}
+
+// runtimeGroupModelV0 describes the data model of version 0.
+type runtimeGroupModelV0 struct {
+	Id                   types.String `tfsdk:"id"`
+	Name                 types.String `tfsdk:"name"`
+	Description          types.String `tfsdk:"description"`
+	ClusterType          types.String `tfsdk:"cluster_type"`
+	Labels               types.Map    `tfsdk:"labels"`
+	ControlPlaneEndpoint types.String `tfsdk:"control_plane_endpoint"`
+	TelemetryEndpoint    types.String `tfsdk:"telemetry_endpoint"`
+}
+
+// runtimeGroupSchemaV0 is the schema of the releases before the label fix. It
+// is frozen rather than derived from Schema, so that the attributes added since
+// are not expected in their states. The newer attributes are left null by the
+// upgrade, to be filled in by the next Read or plan.
+func runtimeGroupSchemaV0() schema.Schema {
+	return schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required: true,
+			},
+			"description": schema.StringAttribute{
+				Optional: true,
+			},
+			"cluster_type": schema.StringAttribute{
+				Optional: true,
+			},
+			"labels": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"control_plane_endpoint": schema.StringAttribute{
+				Computed: true,
+			},
+			"telemetry_endpoint": schema.StringAttribute{
+				Computed: true,
+			},
+			"id": schema.StringAttribute{
+				Computed: true,
 			},
 		},
 	}
 }
 
+// upgradeRuntimeGroupStateV0 strips the quotes the releases before the label
+// fix stored around the label values, e.g. "\"dev\"" for dev, so that the
+// first plan after the upgrade shows no spurious diff.
+func upgradeRuntimeGroupStateV0(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+	var prior runtimeGroupModelV0
+
+	resp.Diagnostics.Append(req.State.Get(ctx, &prior)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !prior.Labels.IsNull() && !prior.Labels.IsUnknown() {
+		labels := make(map[string]string)
+		resp.Diagnostics.Append(prior.Labels.ElementsAs(ctx, &labels, false)...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		for k, v := range labels {
+			labels[k] = unquoteLabelValue(v)
+		}
+
+		var diags diag.Diagnostics
+		prior.Labels, diags = types.MapValueFrom(ctx, types.StringType, labels)
+		resp.Diagnostics.Append(diags...)
+	}
+
+	// The attributes added since version 0 are left null.
+	resp.State.Raw = tftypes.NewValue(resp.State.Schema.Type().TerraformType(ctx), nil)
+	for name, value := range map[string]attr.Value{
+		"id":                     prior.Id,
+		"name":                   prior.Name,
+		"description":            prior.Description,
+		"cluster_type":           prior.ClusterType,
+		"labels":                 prior.Labels,
+		"control_plane_endpoint": prior.ControlPlaneEndpoint,
+		"telemetry_endpoint":     prior.TelemetryEndpoint,
+	} {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(name), value)...)
+	}
+}
+
+// unquoteLabelValue returns value without the surrounding double quotes of a
+// Go quoted string, value itself when it is not one. Such values are assumed to
+// have been quoted by the releases before the label fix.
+func unquoteLabelValue(value string) string {
+	if len(value) < 2 || !strings.HasPrefix(value, `"`) || !strings.HasSuffix(value, `"`) {
+		return value
+	}
+
+	unquoted, err := strconv.Unquote(value)
+	if err != nil {
+		return value
+	}
+
+	return unquoted
+}
+
+func (r *RuntimeGroup) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var data RuntimeGroupModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !data.Name.IsNull() && !data.NamePrefix.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("name_prefix"),
+			"Conflicting Attributes",
+			"Only one of name and name_prefix can be set.",
+		)
+	}
+
+	if data.Name.IsNull() && data.NamePrefix.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("name"),
+			"Missing Attribute",
+			"One of name or name_prefix must be set.",
+		)
+	}
+
+	if !data.TransferOnDestroy.IsNull() && data.OnDestroy.ValueString() == destroyActionArchive {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("transfer_on_destroy"),
+			"Conflicting Attributes",
+			"The runtime group cannot be both transferred and archived on destroy.",
+		)
+	}
+}
+
+func (r *RuntimeGroup) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing is rotated on create or destroy.
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	// The labels read back on apply are not known until then.
+	var priorLabels, plannedLabels types.Map
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("labels"), &priorLabels)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("labels"), &plannedLabels)...)
+
+	if !resp.Diagnostics.HasError() && !plannedLabels.Equal(priorLabels) {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("all_labels"), types.MapUnknown(types.StringType))...)
+	}
+
+	var prior, planned types.String
+	resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("rotate_on"), &prior)...)
+	resp.Diagnostics.Append(req.Plan.GetAttribute(ctx, path.Root("rotate_on"), &planned)...)
+
+	if resp.Diagnostics.HasError() || !rotationTriggered(prior, planned) {
+		return
+	}
+
+	// The rotation changes the endpoints, so their prior values kept by
+	// UseStateForUnknown are not known anymore.
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("control_plane_endpoint"), types.StringUnknown())...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("telemetry_endpoint"), types.StringUnknown())...)
+}
+
 func (r *RuntimeGroup) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	// Prevent panic if the provider has not been configured.
 	if req.ProviderData == nil {
 		return
 	}
 
-	client, ok := req.ProviderData.(*client.Client)
+	clients, ok := req.ProviderData.(*clientRef)
 
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Resource Configure Type",
-			fmt.Sprintf("Expected *http.Client, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *clientRef, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
 	}
 
-	r.client = client
+	r.clients = clients
 }
 
 func (r *RuntimeGroup) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
@@ -113,27 +477,91 @@ func (r *RuntimeGroup) Create(ctx context.Context, req resource.CreateRequest, r
 		return
 	}
 
-	labels := make(map[string]string)
-	for k, v := range data.Labels.Elements() {
-		labels[k] = v.String()
+	createTimeout, diags := data.Timeouts.Create(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	createReq := client.CreateRuntimeGroupRequest{
-		Name:        data.Name.ValueString(),
-		Description: data.Description.ValueString(),
-		ClusterType: data.ClusterType.ValueString(),
-		Labels:      labels,
+	ctx, cancel, timer := startOperation(ctx, "create", createTimeout)
+	defer cancel()
+
+	if data.Name.IsUnknown() || data.Name.IsNull() {
+		data.Name = types.StringValue(client.UniqueName(data.NamePrefix.ValueString()))
+	}
+
+	createReq, diags := data.toCreateRequest(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The cluster_type of the resource wins over the provider default_cluster_type.
+	if createReq.ClusterType == "" {
+		createReq.ClusterType = r.clients.Get().DefaultClusterType()
+	}
+
+	if data.CheckNameUniqueness.ValueBool() {
+		_, err := r.clients.Get().GetRuntimeGroupByName(ctx, createReq.Name)
+		if err == nil {
+			resp.Diagnostics.Append(nameConflictError(createReq.Name))
+			return
+		}
+		if !errors.Is(err, client.ErrNotFound) {
+			timer.addError(&resp.Diagnostics, "checking the name uniqueness", "check runtime group name uniqueness", err)
+			return
+		}
 	}
 
-	createResp, err := r.client.CreateRuntimeGroup(createReq)
+	job, err := r.clients.Get().CreateRuntimeGroupAsync(ctx, createReq)
+	if errors.Is(err, client.ErrConflict) {
+		resp.Diagnostics.Append(nameConflictError(createReq.Name))
+		return
+	}
+	if err != nil {
+		timer.addError(&resp.Diagnostics, "creating the runtime group", "create runtime group", err)
+		return
+	}
+
+	// Creates the API accepted asynchronously are polled until the runtime
+	// group exists, the others are done already.
+	createResp, err := job.Wait(ctx)
 	if err != nil {
-		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to create, got error: %s", err))
+		if id := job.ID(); id != "" {
+			err = fmt.Errorf("runtime group %s, which may need to be imported: %w", id, err)
+		}
+		timer.addError(&resp.Diagnostics, "waiting for the runtime group to be created", "wait for runtime group creation", err)
 		return
 	}
 
+	// From here on the runtime group exists, so no failure returns early: the
+	// diagnostics accumulate and the state is always saved with its id.
 	data.ControlPlaneEndpoint = types.StringValue(createResp.Config.ControlPlaneEndpoint)
 	data.TelemetryEndpoint = types.StringValue(createResp.Config.TelemetryEndpoint)
-	data.Id = data.Name
+	resp.Diagnostics.Append(endpointWarnings(createResp.Config)...)
+	data.Id = types.StringValue(createResp.ID)
+	data.ClusterType = clusterTypeValue(createResp.Config.ClusterType, createReq.ClusterType)
+	data.AllLabels, diags = allLabels(ctx, createResp.Labels)
+	resp.Diagnostics.Append(diags...)
+	data.RawResponse = types.StringValue(string(createResp.Raw))
+	data.EntityVersion = types.Int64PointerValue(createResp.EntityVersion)
+
+	// The runtime group exists at this point, a missing status must not fail the apply.
+	data.Status, err = runtimeGroupStatus(ctx, r.clients.Get(), createResp.ID)
+	if err != nil {
+		resp.Diagnostics.Append(warningFromAPIError("read runtime group status", err)...)
+	}
+
+	// The state is saved even if the runtime group does not become ready, so
+	// that Terraform taints it rather than losing track of it.
+	if data.WaitForReady.ValueBool() && !readyState(data.Status) {
+		data.Status, err = waitForReady(ctx, r.clients.Get(), createResp.ID)
+		if err != nil {
+			timer.addError(&resp.Diagnostics, "waiting for the runtime group to be ready", "wait for the runtime group to be ready", err)
+		}
+	}
 
 	// Save data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -149,35 +577,189 @@ func (r *RuntimeGroup) Read(ctx context.Context, req resource.ReadRequest, resp
 		return
 	}
 
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider client data and make a call using it.
-	// httpResp, err := r.client.Do(httpReq)
-	// if err != nil {
-	//     resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to read example, got error: %s", err))
-	//     return
-	// }
+	readTimeout, diags := data.Timeouts.Read(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel, timer := startOperation(ctx, "read", readTimeout)
+	defer cancel()
+
+	group, err := r.clients.Get().GetRuntimeGroupWithOptions(ctx, data.Id.ValueString(), client.GetOptions{
+		Include: []string{client.IncludeStatus},
+	})
+	if errors.Is(err, client.ErrNotFound) {
+		// The runtime group was deleted outside of Terraform.
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		timer.addError(&resp.Diagnostics, "reading the runtime group", "read runtime group", err)
+		return
+	}
+
+	data.Name = types.StringValue(group.Name)
+	// The cluster type is kept when the API does not return it.
+	if group.Config.ClusterType != "" {
+		data.ClusterType = types.StringValue(string(group.Config.ClusterType))
+	}
+	// Imported runtime groups have no deletion protection until configured.
+	if data.DeletionProtection.IsNull() {
+		data.DeletionProtection = types.BoolValue(false)
+	}
+	if data.OnDestroy.IsNull() {
+		data.OnDestroy = types.StringValue(destroyActionDelete)
+	}
+	if data.CheckNameUniqueness.IsNull() {
+		data.CheckNameUniqueness = types.BoolValue(false)
+	}
+	if data.WaitForReady.IsNull() {
+		data.WaitForReady = types.BoolValue(false)
+	}
+	if data.UpdateStrategy.IsNull() {
+		data.UpdateStrategy = types.StringValue(updateStrategyMerge)
+	}
+	if group.Description != "" || !data.Description.IsNull() {
+		data.Description = types.StringValue(group.Description)
+	}
+	data.ControlPlaneEndpoint = types.StringValue(group.Config.ControlPlaneEndpoint)
+	data.TelemetryEndpoint = types.StringValue(group.Config.TelemetryEndpoint)
+	resp.Diagnostics.Append(endpointWarnings(group.Config)...)
+	data.RawResponse = types.StringValue(string(group.Raw))
+	data.EntityVersion = types.Int64PointerValue(group.EntityVersion)
+
+	// The cluster type is resolved as on create and update, so that the default
+	// labels expand to the values sent.
+	clusterType := client.ClusterType(data.ClusterType.ValueString())
+	if clusterType == "" {
+		clusterType = r.clients.Get().DefaultClusterType()
+	}
+
+	defaults := r.clients.Get().DefaultLabelsFor(group.Name, clusterType)
+	resp.Diagnostics.Append(data.readLabels(ctx, group.Labels, defaults)...)
+	data.AllLabels, diags = allLabels(ctx, group.Labels)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.Status, err = includedStatus(ctx, r.clients.Get(), group)
+	if err != nil {
+		timer.addError(&resp.Diagnostics, "reading the runtime group status", "read runtime group status", err)
+		return
+	}
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
 }
 
 func (r *RuntimeGroup) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data RuntimeGroupModel
+	var data, state RuntimeGroupModel
 
-	// Read Terraform plan data into the model
+	// Read Terraform plan and prior state data into the models
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The API cannot change the cluster type of a runtime group. The plan
+	// modifier replaces the resource instead, guard against it being bypassed
+	// rather than sending an update the API rejects.
+	if !data.ClusterType.Equal(state.ClusterType) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("cluster_type"),
+			"Runtime Group Replacement Required",
+			fmt.Sprintf("The cluster type of the runtime group %q cannot be changed from %s to %s in place. "+
+				"The runtime group must be replaced, e.g. with terraform apply -replace.",
+				state.Name.ValueString(), state.ClusterType, data.ClusterType),
+		)
+		return
+	}
+
+	updateTimeout, diags := data.Timeouts.Update(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+
+	changes, diags := data.toCreateRequest(ctx)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Every update sends the whole labels, the ignored ones included.
+	resp.Diagnostics.Append(data.keepIgnoredLabels(ctx, changes.Labels, state.AllLabels)...)
 
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider client data and make a call using it.
-	// httpResp, err := r.client.Do(httpReq)
-	// if err != nil {
-	//     resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to update example, got error: %s", err))
-	//     return
-	// }
+	ctx, cancel, timer := startOperation(ctx, "update", updateTimeout)
+	defer cancel()
+
+	var priorLabels map[string]string
+	resp.Diagnostics.Append(state.Labels.ElementsAs(ctx, &priorLabels, false)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Unchanged fields are sent too, from the plan which matches the state for
+	// them, so an update of the labels alone keeps the description also with
+	// APIs replacing the whole runtime group.
+	// The cluster type is unchanged, see above, and resolved as on create.
+	if changes.ClusterType == "" {
+		changes.ClusterType = r.clients.Get().DefaultClusterType()
+	}
+
+	updateReq := client.UpdateRuntimeGroupRequest{
+		Name:        changes.Name,
+		Description: changes.Description,
+		Labels:      changes.Labels,
+		PriorLabels: priorLabels,
+		ClusterType: changes.ClusterType,
+	}
+
+	// Only the labels are sent when nothing else changed, the client falls
+	// back to a full update when the API has no labels subresource.
+	labelsOnly := changes.Name == state.Name.ValueString() &&
+		changes.Description == state.Description.ValueString() &&
+		!data.Labels.Equal(state.Labels)
+
+	var updateResp *client.CreateRuntimeGroupResponse
+	var err error
+	switch {
+	case data.UpdateStrategy.ValueString() == updateStrategyReplace:
+		updateResp, err = r.clients.Get().ReplaceRuntimeGroup(ctx, data.Id.ValueString(), changes)
+	case labelsOnly:
+		updateResp, err = r.clients.Get().UpdateRuntimeGroupLabels(ctx, data.Id.ValueString(), changes.Labels)
+	default:
+		updateResp, err = r.clients.Get().UpdateRuntimeGroup(ctx, data.Id.ValueString(), updateReq)
+	}
+	if err != nil {
+		timer.addError(&resp.Diagnostics, "updating the runtime group", "update runtime group", err)
+		return
+	}
+
+	if rotationTriggered(state.RotateOn, data.RotateOn) {
+		updateResp, err = r.clients.Get().RotateRuntimeGroupCredentials(ctx, data.Id.ValueString())
+		if err != nil {
+			timer.addError(&resp.Diagnostics, "rotating the runtime group credentials", "rotate runtime group credentials", err)
+			return
+		}
+	}
+
+	data.ControlPlaneEndpoint = types.StringValue(updateResp.Config.ControlPlaneEndpoint)
+	data.TelemetryEndpoint = types.StringValue(updateResp.Config.TelemetryEndpoint)
+	resp.Diagnostics.Append(endpointWarnings(updateResp.Config)...)
+	data.AllLabels, diags = allLabels(ctx, updateResp.Labels)
+	resp.Diagnostics.Append(diags...)
+	data.RawResponse = types.StringValue(string(updateResp.Raw))
+	data.EntityVersion = types.Int64PointerValue(updateResp.EntityVersion)
 
 	// Save updated data into Terraform state
 	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
@@ -193,15 +775,391 @@ func (r *RuntimeGroup) Delete(ctx context.Context, req resource.DeleteRequest, r
 		return
 	}
 
-	// If applicable, this is a great opportunity to initialize any necessary
-	// provider client data and make a call using it.
-	// httpResp, err := r.client.Do(httpReq)
-	// if err != nil {
-	//     resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to delete example, got error: %s", err))
-	//     return
-	// }
+	if data.DeletionProtection.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("deletion_protection"),
+			"Runtime Group Deletion Protected",
+			fmt.Sprintf("The runtime group %q has deletion protection enabled. Set deletion_protection to false and apply "+
+				"the change before destroying it.", data.Name.ValueString()),
+		)
+		return
+	}
+
+	deleteTimeout, diags := data.Timeouts.Delete(ctx, defaultTimeout)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	ctx, cancel, timer := startOperation(ctx, "delete", deleteTimeout)
+	defer cancel()
+
+	if !data.TransferOnDestroy.IsNull() {
+		err := r.clients.Get().TransferRuntimeGroup(ctx, data.Id.ValueString(), data.TransferOnDestroy.ValueString())
+		if errors.Is(err, client.ErrNotFound) {
+			// The runtime group was already deleted outside of Terraform.
+			return
+		}
+		if err != nil {
+			timer.addError(&resp.Diagnostics, "transferring the runtime group", "transfer runtime group", err)
+		}
+		return
+	}
+
+	if data.OnDestroy.ValueString() == destroyActionArchive {
+		err := r.clients.Get().ArchiveRuntimeGroup(ctx, data.Id.ValueString())
+		if errors.Is(err, client.ErrNotFound) {
+			// The runtime group was already deleted outside of Terraform.
+			return
+		}
+		if err != nil {
+			timer.addError(&resp.Diagnostics, "archiving the runtime group", "archive runtime group", err)
+		}
+		return
+	}
+
+	err := r.clients.Get().DeleteRuntimeGroup(ctx, data.Id.ValueString())
+	if errors.Is(err, client.ErrNotFound) {
+		// The runtime group was already deleted outside of Terraform.
+		return
+	}
+	if err != nil {
+		timer.addError(&resp.Diagnostics, "deleting the runtime group", "delete runtime group", err)
+		return
+	}
 }
 
+// ImportState resolves the import id to the id of the runtime group. The other
+// attributes are left to the Read that the framework runs after the import.
 func (r *RuntimeGroup) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	if runtimeGroupIdPattern.MatchString(req.ID) {
+		// A mistyped id would otherwise be imported and only fail, confusingly,
+		// on the next plan.
+		_, err := r.clients.Get().GetRuntimeGroup(ctx, req.ID)
+		if errors.Is(err, client.ErrNotFound) {
+			resp.Diagnostics.AddError(
+				"Runtime Group Not Found",
+				fmt.Sprintf("No runtime group with id %q exists in the organization of the token. Check the import id, "+
+					"which must have one of the following formats:\n\n%s", req.ID, runtimeGroupImportHelp()),
+			)
+			return
+		}
+		if err != nil {
+			resp.Diagnostics.Append(diagFromAPIError("get runtime group", err)...)
+			return
+		}
+
+		resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+		return
+	}
+
+	name, clusterType, err := parseRuntimeGroupImportID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Invalid Import ID",
+			fmt.Sprintf("%s. The import id must have one of the following formats:\n\n%s", err, runtimeGroupImportHelp()),
+		)
+		return
+	}
+
+	// Names are unique within an organization, so that at most one runtime group
+	// matches.
+	opts := client.ListOptions{Name: name}
+	if clusterType != "" {
+		opts.ClusterTypes = []client.ClusterType{client.ClusterType(clusterType)}
+	}
+
+	listResp, err := r.clients.Get().ListAllRuntimeGroups(ctx, opts)
+	if err != nil {
+		resp.Diagnostics.Append(diagFromAPIError("list runtime groups", err)...)
+		return
+	}
+
+	// Servers ignoring the filter return every runtime group.
+	id := ""
+	for _, group := range listResp.Data {
+		if group.Name == name {
+			id = group.ID
+			break
+		}
+	}
+
+	if id == "" {
+		resp.Diagnostics.AddError(
+			"Runtime Group Not Found",
+			fmt.Sprintf("No runtime group matches the import id %q. The import id must have one of the following formats:\n\n%s", req.ID, runtimeGroupImportHelp()),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+	if clusterType != "" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("cluster_type"), clusterType)...)
+	}
+}
+
+// parseRuntimeGroupImportID splits a `<name>` or `<cluster_type>/<name>` import
+// id into its name and cluster type.
+func parseRuntimeGroupImportID(id string) (name string, clusterType string, err error) {
+	name = id
+	if before, after, found := strings.Cut(id, "/"); found {
+		clusterType, name = before, after
+
+		if !client.ClusterType(clusterType).Valid() {
+			return "", "", fmt.Errorf("unknown cluster type %q", clusterType)
+		}
+	}
+
+	if name == "" {
+		return "", "", fmt.Errorf("import id %q has an empty name", id)
+	}
+
+	return name, clusterType, nil
+}
+
+// nameConflictError reports that a runtime group with the given name exists,
+// hinting at importing it by name.
+func nameConflictError(name string) diag.Diagnostic {
+	return diag.NewAttributeErrorDiagnostic(
+		path.Root("name"),
+		"Runtime Group Already Exists",
+		fmt.Sprintf("A runtime group named %q already exists. To manage it with Terraform, import it instead of creating it: "+
+			"terraform import <resource address> %q", name, name),
+	)
+}
+
+// rotationTriggered reports whether rotate_on changed from prior to planned.
+// Removing the trigger does not rotate the credentials.
+func rotationTriggered(prior, planned types.String) bool {
+	return !planned.IsNull() && !planned.Equal(prior)
+}
+
+// clusterTypeValue returns the cluster type returned by the API, or else the
+// requested one, null when neither is known.
+func clusterTypeValue(returned, requested client.ClusterType) types.String {
+	switch {
+	case returned != "":
+		return types.StringValue(string(returned))
+	case requested != "":
+		return types.StringValue(string(requested))
+	default:
+		return types.StringNull()
+	}
+}
+
+// endpointWarnings warns about the endpoints of config that are not valid
+// http(s) URLs, or that are identical, a symptom of a misconfigured backend
+// duplicating its config. They are still stored as is, the warning lets
+// operators notice backend issues.
+func endpointWarnings(config client.RuntimeGroupConfig) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if config.ControlPlaneEndpoint != "" && config.ControlPlaneEndpoint == config.TelemetryEndpoint {
+		diags.AddAttributeWarning(
+			path.Root("telemetry_endpoint"),
+			"Duplicated Endpoint",
+			fmt.Sprintf("The API returned the same control_plane_endpoint and telemetry_endpoint, %s. "+
+				"The backend may be misconfigured, data planes are unlikely to report telemetry.", config.ControlPlaneEndpoint),
+		)
+	}
+
+	endpoints := map[string]string{
+		"control_plane_endpoint": config.ControlPlaneEndpoint,
+		"telemetry_endpoint":     config.TelemetryEndpoint,
+	}
+
+	for attribute, endpoint := range endpoints {
+		if endpoint == "" {
+			continue
+		}
+
+		if err := client.ValidateEndpoint(endpoint); err != nil {
+			diags.AddAttributeWarning(
+				path.Root(attribute),
+				"Malformed Endpoint",
+				fmt.Sprintf("The API returned a malformed %s, got error: %s", attribute, err),
+			)
+		}
+	}
+
+	return diags
+}
+
+// toCreateRequest converts the model to the body of a create request, also
+// used for the fields shared with update requests. Labels are never nil: null
+// labels send an empty object which clears them on update, rather than omitting
+// them which would leave them untouched.
+func (m RuntimeGroupModel) toCreateRequest(ctx context.Context) (client.CreateRuntimeGroupRequest, diag.Diagnostics) {
+	var labels map[string]string
+	diags := m.Labels.ElementsAs(ctx, &labels, false)
+
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	return client.CreateRuntimeGroupRequest{
+		Name:        m.Name.ValueString(),
+		Description: m.Description.ValueString(),
+		ClusterType: client.ClusterType(m.ClusterType.ValueString()),
+		Labels:      labels,
+	}, diags
+}
+
+// readLabels stores the labels read from the API into the model, leaving out
+// the keys listed in ignore_labels, the labels added by Konnect and the
+// unchanged provider default labels the resource does not set itself.
+func (m *RuntimeGroupModel) readLabels(ctx context.Context, labels map[string]string, defaults map[string]string) diag.Diagnostics {
+	var ignored []string
+	diags := m.IgnoreLabels.ElementsAs(ctx, &ignored, false)
+
+	if diags.HasError() {
+		return diags
+	}
+
+	labels = filterLabels(labels, ignored)
+
+	// Konnect adds labels under its reserved prefixes, users cannot set them.
+	for k := range labels {
+		if reservedLabelKey(k) {
+			delete(labels, k)
+		}
+	}
+
+	// Default labels are merged in on create, they are not drift.
+	priorLabels := m.Labels.Elements()
+	for k, v := range defaults {
+		if _, ok := priorLabels[k]; !ok && labels[k] == v {
+			delete(labels, k)
+		}
+	}
+
+	// Keep an unset labels attribute null rather than an empty map.
+	if len(labels) == 0 && m.Labels.IsNull() {
+		return diags
+	}
+
+	var labelsDiags diag.Diagnostics
+	m.Labels, labelsDiags = types.MapValueFrom(ctx, types.StringType, labels)
+	diags.Append(labelsDiags...)
+
+	return diags
+}
+
+// keepIgnoredLabels adds to labels the current values, from current, of the
+// keys listed in ignore_labels, so that an update does not remove them. The
+// labels set in the configuration win, and the labels under the reserved
+// prefixes, which cannot be sent, are kept by Konnect itself.
+func (m RuntimeGroupModel) keepIgnoredLabels(ctx context.Context, labels map[string]string, current types.Map) diag.Diagnostics {
+	var ignored []string
+	diags := m.IgnoreLabels.ElementsAs(ctx, &ignored, false)
+
+	var currentLabels map[string]string
+	diags.Append(current.ElementsAs(ctx, &currentLabels, false)...)
+
+	if diags.HasError() {
+		return diags
+	}
+
+	for _, key := range ignored {
+		v, ok := currentLabels[key]
+		if _, set := labels[key]; ok && !set && !reservedLabelKey(key) {
+			labels[key] = v
+		}
+	}
+
+	return diags
+}
+
+// allLabels returns the all_labels value of the labels read from the API,
+// empty rather than null when there are none.
+func allLabels(ctx context.Context, labels map[string]string) (types.Map, diag.Diagnostics) {
+	if labels == nil {
+		labels = map[string]string{}
+	}
+
+	return types.MapValueFrom(ctx, types.StringType, labels)
+}
+
+// runtimeGroupStatus returns the status of the runtime group with the given id,
+// null when the API does not expose the status subresource.
+func runtimeGroupStatus(ctx context.Context, c *client.Client, id string) (types.String, error) {
+	status, err := c.GetRuntimeGroupStatus(ctx, id)
+	if errors.Is(err, client.ErrNotFound) || errors.Is(err, client.ErrNotSupported) {
+		return types.StringNull(), nil
+	}
+	if err != nil {
+		return types.StringNull(), err
+	}
+
+	return types.StringValue(status.State), nil
+}
+
+// includedStatus returns the status expanded in group, see client.IncludeStatus,
+// or fetches it when the API did not include it.
+func includedStatus(ctx context.Context, c *client.Client, group *client.CreateRuntimeGroupResponse) (types.String, error) {
+	if group.Status != nil {
+		return types.StringValue(group.Status.State), nil
+	}
+
+	return runtimeGroupStatus(ctx, c, group.ID)
+}
+
+// readyStates are the statuses of a runtime group whose data plane is connected.
+var readyStates = []string{"ready", "connected"}
+
+// readyPollInterval is the delay between the status reads of waitForReady.
+var readyPollInterval = 5 * time.Second
+
+// readyState reports whether status is one of readyStates.
+func readyState(status types.String) bool {
+	for _, state := range readyStates {
+		if strings.EqualFold(status.ValueString(), state) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// waitForReady polls the status of the runtime group with the given id until
+// it is ready, failing with the context error once ctx is done.
+func waitForReady(ctx context.Context, c *client.Client, id string) (types.String, error) {
+	ticker := time.NewTicker(readyPollInterval)
+	defer ticker.Stop()
+
+	for {
+		status, err := runtimeGroupStatus(ctx, c, id)
+		if err != nil {
+			return status, err
+		}
+
+		if status.IsNull() {
+			return status, fmt.Errorf("the API does not expose the runtime group status")
+		}
+
+		if readyState(status) {
+			return status, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return status, fmt.Errorf("runtime group still %q: %w", status.ValueString(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// filterLabels returns a copy of labels without the ignored keys.
+func filterLabels(labels map[string]string, ignored []string) map[string]string {
+	filtered := make(map[string]string, len(labels))
+	for k, v := range labels {
+		filtered[k] = v
+	}
+
+	for _, key := range ignored {
+		delete(filtered, key)
+	}
+
+	return filtered
 }
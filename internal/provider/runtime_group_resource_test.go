@@ -0,0 +1,1686 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/client"
+)
+
+// runtimeGroupSchema returns the schema of the runtime group resource.
+func runtimeGroupSchema(t *testing.T) resource.SchemaResponse {
+	t.Helper()
+
+	var resp resource.SchemaResponse
+	(&RuntimeGroup{}).Schema(context.Background(), resource.SchemaRequest{}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	return resp
+}
+
+// stringMap returns a Terraform map of strings value.
+func stringMap(values map[string]string) tftypes.Value {
+	elements := make(map[string]tftypes.Value, len(values))
+	for k, v := range values {
+		elements[k] = tftypes.NewValue(tftypes.String, v)
+	}
+
+	return tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, elements)
+}
+
+// stringSet returns a Terraform set of strings value.
+func stringSet(values ...string) tftypes.Value {
+	elements := make([]tftypes.Value, len(values))
+	for i, v := range values {
+		elements[i] = tftypes.NewValue(tftypes.String, v)
+	}
+
+	return tftypes.NewValue(tftypes.Set{ElementType: tftypes.String}, elements)
+}
+
+// readRuntimeGroup runs the resource Read for the given prior state against a
+// stub server answering the given routes.
+func readRuntimeGroup(t *testing.T, routes map[string]string, state map[string]tftypes.Value, opts ...client.Option) RuntimeGroupModel {
+	t.Helper()
+
+	server := testServer(t, routes)
+
+	ctx := context.Background()
+	r := &RuntimeGroup{clients: newClientRef(testClient(t, server.URL, opts...))}
+	schemaResp := runtimeGroupSchema(t)
+
+	priorState := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    testObject(t, schemaResp.Schema.Type(), state),
+	}
+	resp := resource.ReadResponse{State: priorState}
+
+	r.Read(ctx, resource.ReadRequest{State: priorState}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data RuntimeGroupModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	return data
+}
+
+// createRuntimeGroup runs the resource Create for the given plan.
+func createRuntimeGroup(t *testing.T, c *client.Client, plan map[string]tftypes.Value) resource.CreateResponse {
+	t.Helper()
+
+	ctx := context.Background()
+	r := &RuntimeGroup{clients: newClientRef(c)}
+	schemaResp := runtimeGroupSchema(t)
+
+	req := resource.CreateRequest{
+		Plan: tfsdk.Plan{
+			Schema: schemaResp.Schema,
+			Raw:    testObject(t, schemaResp.Schema.Type(), plan),
+		},
+	}
+	resp := resource.CreateResponse{
+		State: tfsdk.State{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+		},
+	}
+
+	r.Create(ctx, req, &resp)
+
+	return resp
+}
+
+func TestRuntimeGroupCreateConflict(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"status":409,"title":"Conflict","detail":"Key (org_id, name) already exists."}`)
+	}))
+	defer server.Close()
+
+	resp := createRuntimeGroup(t, testClient(t, server.URL), map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "test"),
+	})
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error diagnostic")
+	}
+
+	diags := resp.Diagnostics.Errors()
+	if diags[0].Summary() != "Runtime Group Already Exists" || !strings.Contains(diags[0].Detail(), `terraform import <resource address> "test"`) {
+		t.Fatalf("unexpected diagnostic: %s: %s", diags[0].Summary(), diags[0].Detail())
+	}
+
+	if _, ok := diags[0].(interface{ Path() path.Path }); !ok {
+		t.Fatalf("expected an attribute diagnostic, got %T", diags[0])
+	}
+}
+
+func TestRuntimeGroupCreateCheckNameUniqueness(t *testing.T) {
+	tests := map[string]struct {
+		existing    string
+		wantError   bool
+		wantCreated bool
+	}{
+		"unique":    {existing: "other", wantCreated: true},
+		"duplicate": {existing: "test", wantError: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var created bool
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == "/runtime-groups":
+					fmt.Fprintf(w, `{"meta":{"page":{"number":1,"size":10,"total":1}},"data":[{"id":"rg-0","name":%q}]}`, tt.existing)
+				case r.Method == http.MethodPost:
+					created = true
+					w.WriteHeader(http.StatusCreated)
+					fmt.Fprint(w, `{"id":"rg-1","name":"test"}`)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}))
+			defer server.Close()
+
+			resp := createRuntimeGroup(t, testClient(t, server.URL), map[string]tftypes.Value{
+				"name":                  tftypes.NewValue(tftypes.String, "test"),
+				"check_name_uniqueness": tftypes.NewValue(tftypes.Bool, true),
+			})
+
+			if resp.Diagnostics.HasError() != tt.wantError {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			if tt.wantError && resp.Diagnostics.Errors()[0].Summary() != "Runtime Group Already Exists" {
+				t.Fatalf("unexpected diagnostic: %v", resp.Diagnostics)
+			}
+
+			if created != tt.wantCreated {
+				t.Fatalf("expected created to be %t, got %t", tt.wantCreated, created)
+			}
+		})
+	}
+}
+
+// updateRuntimeGroup runs the resource Update for the given plan and prior state.
+func updateRuntimeGroup(t *testing.T, c *client.Client, plan, state map[string]tftypes.Value) resource.UpdateResponse {
+	t.Helper()
+
+	ctx := context.Background()
+	r := &RuntimeGroup{clients: newClientRef(c)}
+	schemaResp := runtimeGroupSchema(t)
+
+	priorState := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    testObject(t, schemaResp.Schema.Type(), state),
+	}
+	req := resource.UpdateRequest{
+		Plan: tfsdk.Plan{
+			Schema: schemaResp.Schema,
+			Raw:    testObject(t, schemaResp.Schema.Type(), plan),
+		},
+		State: priorState,
+	}
+	resp := resource.UpdateResponse{State: priorState}
+
+	r.Update(ctx, req, &resp)
+
+	return resp
+}
+
+func TestRuntimeGroupUpdateStrategy(t *testing.T) {
+	tests := map[string]struct {
+		strategy   tftypes.Value
+		rename     bool
+		wantMethod string
+		wantPath   string
+		wantBody   string
+	}{
+		"default": {
+			strategy:   tftypes.NewValue(tftypes.String, nil),
+			wantMethod: http.MethodPatch,
+			wantPath:   "/runtime-groups/rg-1/labels",
+			wantBody:   `{"env":"prod"}`,
+		},
+		"merge": {
+			strategy:   tftypes.NewValue(tftypes.String, "merge"),
+			wantMethod: http.MethodPatch,
+			wantPath:   "/runtime-groups/rg-1/labels",
+			wantBody:   `{"env":"prod"}`,
+		},
+		"merge with a renaming": {
+			strategy:   tftypes.NewValue(tftypes.String, "merge"),
+			rename:     true,
+			wantMethod: http.MethodPatch,
+			wantPath:   "/runtime-groups/rg-1",
+			wantBody:   `{"name":"renamed","description":"","labels":{"env":"prod"}}`,
+		},
+		"replace": {
+			strategy:   tftypes.NewValue(tftypes.String, "replace"),
+			wantMethod: http.MethodPut,
+			wantPath:   "/runtime-groups/rg-1",
+			wantBody:   `{"name":"test","description":"","cluster_type":"CLUSTER_TYPE_HYBRID","labels":{"env":"prod"}}`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Errorf("reading request body: %s", err)
+				}
+
+				if r.Method != tt.wantMethod || r.URL.Path != tt.wantPath || string(body) != tt.wantBody {
+					t.Errorf("expected %s %s %s, got %s %s %s", tt.wantMethod, tt.wantPath, tt.wantBody, r.Method, r.URL.Path, body)
+				}
+
+				fmt.Fprint(w, `{"id":"rg-1","name":"test","labels":{"env":"prod"}}`)
+			}))
+			defer server.Close()
+
+			state := map[string]tftypes.Value{
+				"id":              tftypes.NewValue(tftypes.String, "rg-1"),
+				"name":            tftypes.NewValue(tftypes.String, "test"),
+				"cluster_type":    tftypes.NewValue(tftypes.String, "CLUSTER_TYPE_HYBRID"),
+				"labels":          stringMap(map[string]string{"env": "dev"}),
+				"update_strategy": tt.strategy,
+			}
+			plan := copyValues(state)
+			plan["labels"] = stringMap(map[string]string{"env": "prod"})
+			if tt.rename {
+				plan["name"] = tftypes.NewValue(tftypes.String, "renamed")
+			}
+
+			resp := updateRuntimeGroup(t, testClient(t, server.URL), plan, state)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestRuntimeGroupUpdateKeepsIgnoredLabels(t *testing.T) {
+	tests := map[string]struct {
+		strategy tftypes.Value
+		rename   bool
+		wantPath string
+		wantBody string
+	}{
+		"labels only": {
+			strategy: tftypes.NewValue(tftypes.String, "merge"),
+			wantPath: "/runtime-groups/rg-1/labels",
+			wantBody: `{"env":"prod","team":"ops"}`,
+		},
+		"merge with a renaming": {
+			strategy: tftypes.NewValue(tftypes.String, "merge"),
+			rename:   true,
+			wantPath: "/runtime-groups/rg-1",
+			wantBody: `{"name":"renamed","description":"","labels":{"env":"prod","team":"ops"}}`,
+		},
+		"replace": {
+			strategy: tftypes.NewValue(tftypes.String, "replace"),
+			wantPath: "/runtime-groups/rg-1",
+			wantBody: `{"name":"test","description":"","cluster_type":"CLUSTER_TYPE_HYBRID","labels":{"env":"prod","team":"ops"}}`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					t.Errorf("reading request body: %s", err)
+				}
+
+				if r.URL.Path != tt.wantPath || string(body) != tt.wantBody {
+					t.Errorf("expected %s %s, got %s %s", tt.wantPath, tt.wantBody, r.URL.Path, body)
+				}
+
+				fmt.Fprint(w, `{"id":"rg-1","name":"test","labels":{"env":"prod","team":"ops","konnect.created_by":"ui"}}`)
+			}))
+			defer server.Close()
+
+			// The reserved label is not sent, Konnect keeps it.
+			state := map[string]tftypes.Value{
+				"id":              tftypes.NewValue(tftypes.String, "rg-1"),
+				"name":            tftypes.NewValue(tftypes.String, "test"),
+				"cluster_type":    tftypes.NewValue(tftypes.String, "CLUSTER_TYPE_HYBRID"),
+				"labels":          stringMap(map[string]string{"env": "dev"}),
+				"all_labels":      stringMap(map[string]string{"env": "dev", "team": "ops", "konnect.created_by": "ui"}),
+				"ignore_labels":   stringSet("team", "konnect.created_by"),
+				"update_strategy": tt.strategy,
+			}
+			plan := copyValues(state)
+			plan["labels"] = stringMap(map[string]string{"env": "prod"})
+			plan["all_labels"] = tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, tftypes.UnknownValue)
+			if tt.rename {
+				plan["name"] = tftypes.NewValue(tftypes.String, "renamed")
+			}
+
+			resp := updateRuntimeGroup(t, testClient(t, server.URL), plan, state)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var data RuntimeGroupModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+			if want := map[string]attr.Value{"env": types.StringValue("prod")}; !reflect.DeepEqual(data.Labels.Elements(), want) {
+				t.Fatalf("expected labels %v without the ignored labels, got %s", want, data.Labels)
+			}
+		})
+	}
+}
+
+func TestRuntimeGroupUpdateClearsLabels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("reading request body: %s", err)
+		}
+
+		if want := `{}`; r.URL.Path != "/runtime-groups/rg-1/labels" || string(body) != want {
+			t.Errorf("expected body %s to the labels subresource, got %s to %s", want, body, r.URL.Path)
+		}
+
+		fmt.Fprint(w, `{"id":"rg-1","name":"test"}`)
+	}))
+	defer server.Close()
+
+	resp := updateRuntimeGroup(t, testClient(t, server.URL), map[string]tftypes.Value{
+		"id":   tftypes.NewValue(tftypes.String, "rg-1"),
+		"name": tftypes.NewValue(tftypes.String, "test"),
+	}, map[string]tftypes.Value{
+		"id":     tftypes.NewValue(tftypes.String, "rg-1"),
+		"name":   tftypes.NewValue(tftypes.String, "test"),
+		"labels": stringMap(map[string]string{"env": "dev"}),
+	})
+
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+}
+
+func TestRuntimeGroupUpdateLabelsKeepsDescription(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Without a labels subresource, the full update is sent.
+		switch {
+		case r.URL.Path == "/runtime-groups/rg-1/labels":
+			w.WriteHeader(http.StatusNotFound)
+			return
+		case r.Method == http.MethodGet:
+			fmt.Fprint(w, `{"id":"rg-1","name":"test","description":"the description","labels":{"env":"dev"}}`)
+			return
+		}
+
+		var body map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding request body: %s", err)
+		}
+
+		if body["description"] != "the description" {
+			t.Errorf("expected the description to be sent unchanged, got %v", body)
+		}
+
+		fmt.Fprint(w, `{"id":"rg-1","name":"test","description":"the description","labels":{"env":"prod"}}`)
+	}))
+	defer server.Close()
+
+	resp := updateRuntimeGroup(t, testClient(t, server.URL), map[string]tftypes.Value{
+		"id":          tftypes.NewValue(tftypes.String, "rg-1"),
+		"name":        tftypes.NewValue(tftypes.String, "test"),
+		"description": tftypes.NewValue(tftypes.String, "the description"),
+		"labels":      stringMap(map[string]string{"env": "prod"}),
+	}, map[string]tftypes.Value{
+		"id":          tftypes.NewValue(tftypes.String, "rg-1"),
+		"name":        tftypes.NewValue(tftypes.String, "test"),
+		"description": tftypes.NewValue(tftypes.String, "the description"),
+		"labels":      stringMap(map[string]string{"env": "dev"}),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data RuntimeGroupModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+
+	if data.Description.ValueString() != "the description" {
+		t.Fatalf("expected the description to be kept in state, got %s", data.Description)
+	}
+}
+
+func TestRuntimeGroupUpdateClusterTypeChanged(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+
+	resp := updateRuntimeGroup(t, testClient(t, server.URL), map[string]tftypes.Value{
+		"id":           tftypes.NewValue(tftypes.String, "rg-1"),
+		"name":         tftypes.NewValue(tftypes.String, "test"),
+		"cluster_type": tftypes.NewValue(tftypes.String, string(client.ClusterTypeK8sIngressController)),
+	}, map[string]tftypes.Value{
+		"id":           tftypes.NewValue(tftypes.String, "rg-1"),
+		"name":         tftypes.NewValue(tftypes.String, "test"),
+		"cluster_type": tftypes.NewValue(tftypes.String, string(client.ClusterTypeHybrid)),
+	})
+
+	if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Runtime Group Replacement Required" {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	if requests != 0 {
+		t.Fatalf("expected no requests, got %d", requests)
+	}
+}
+
+func TestRuntimeGroupReadIgnoreLabels(t *testing.T) {
+	body := `{"id":"rg-1","name":"test","labels":{"env":"prod","konnect.owner":"someone-else"}}`
+
+	data := readRuntimeGroup(t, map[string]string{"/runtime-groups/rg-1": body}, map[string]tftypes.Value{
+		"id":            tftypes.NewValue(tftypes.String, "rg-1"),
+		"name":          tftypes.NewValue(tftypes.String, "test"),
+		"labels":        stringMap(map[string]string{"env": "prod"}),
+		"ignore_labels": stringSet("konnect.owner"),
+	})
+
+	labels := data.Labels.Elements()
+	if len(labels) != 1 || labels["env"].String() != `"prod"` {
+		t.Fatalf("expected only the env label, got %s", data.Labels)
+	}
+}
+
+func TestRuntimeGroupReadReportsLabelDrift(t *testing.T) {
+	body := `{"id":"rg-1","name":"test","labels":{"env":"prod","owner":"someone-else"}}`
+
+	data := readRuntimeGroup(t, map[string]string{"/runtime-groups/rg-1": body}, map[string]tftypes.Value{
+		"id":     tftypes.NewValue(tftypes.String, "rg-1"),
+		"name":   tftypes.NewValue(tftypes.String, "test"),
+		"labels": stringMap(map[string]string{"env": "prod"}),
+	})
+
+	if _, ok := data.Labels.Elements()["owner"]; !ok {
+		t.Fatalf("expected the owner label without ignore_labels, got %s", data.Labels)
+	}
+}
+
+func TestRuntimeGroupReadServerAddedLabels(t *testing.T) {
+	body := `{"id":"rg-1","name":"test","labels":{"env":"prod","konnect.created_by":"someone"}}`
+
+	data := readRuntimeGroup(t, map[string]string{"/runtime-groups/rg-1": body}, map[string]tftypes.Value{
+		"id":     tftypes.NewValue(tftypes.String, "rg-1"),
+		"name":   tftypes.NewValue(tftypes.String, "test"),
+		"labels": stringMap(map[string]string{"env": "prod"}),
+	})
+
+	ctx := context.Background()
+	labels := make(map[string]string)
+	data.Labels.ElementsAs(ctx, &labels, false)
+	allLabels := make(map[string]string)
+	data.AllLabels.ElementsAs(ctx, &allLabels, false)
+
+	if want := map[string]string{"env": "prod"}; !reflect.DeepEqual(labels, want) {
+		t.Fatalf("expected labels %v, got %v", want, labels)
+	}
+
+	if want := map[string]string{"env": "prod", "konnect.created_by": "someone"}; !reflect.DeepEqual(allLabels, want) {
+		t.Fatalf("expected all_labels %v, got %v", want, allLabels)
+	}
+}
+
+func TestRuntimeGroupModifyPlanAllLabels(t *testing.T) {
+	prior := map[string]tftypes.Value{
+		"id":         tftypes.NewValue(tftypes.String, "rg-1"),
+		"name":       tftypes.NewValue(tftypes.String, "test"),
+		"labels":     stringMap(map[string]string{"env": "dev"}),
+		"all_labels": stringMap(map[string]string{"env": "dev", "konnect.created_by": "someone"}),
+	}
+
+	tests := map[string]struct {
+		labels      map[string]string
+		wantUnknown bool
+	}{
+		"unchanged labels": {labels: map[string]string{"env": "dev"}},
+		"changed labels":   {labels: map[string]string{"env": "prod"}, wantUnknown: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			schemaResp := runtimeGroupSchema(t)
+
+			planned := copyValues(prior)
+			planned["labels"] = stringMap(tt.labels)
+
+			plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: testObject(t, schemaResp.Schema.Type(), planned)}
+			req := resource.ModifyPlanRequest{
+				State: tfsdk.State{Schema: schemaResp.Schema, Raw: testObject(t, schemaResp.Schema.Type(), prior)},
+				Plan:  plan,
+			}
+			resp := resource.ModifyPlanResponse{Plan: plan}
+
+			(&RuntimeGroup{}).ModifyPlan(ctx, req, &resp)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var allLabels types.Map
+			resp.Plan.GetAttribute(ctx, path.Root("all_labels"), &allLabels)
+			if allLabels.IsUnknown() != tt.wantUnknown {
+				t.Fatalf("expected unknown all_labels %t, got %s", tt.wantUnknown, allLabels)
+			}
+		})
+	}
+}
+
+func TestRuntimeGroupReadDefaultLabels(t *testing.T) {
+	body := `{"id":"rg-1","name":"test","labels":{"env":"dev","team":"platform","owner":"ops"}}`
+	defaults := client.WithDefaultLabels(map[string]string{"env": "prod", "team": "platform", "owner": "sre"})
+
+	data := readRuntimeGroup(t, map[string]string{"/runtime-groups/rg-1": body}, map[string]tftypes.Value{
+		"id":     tftypes.NewValue(tftypes.String, "rg-1"),
+		"name":   tftypes.NewValue(tftypes.String, "test"),
+		"labels": stringMap(map[string]string{"env": "dev"}),
+	}, defaults)
+
+	labels := data.Labels.Elements()
+
+	// env is set on the resource and wins over the default.
+	if labels["env"].String() != `"dev"` {
+		t.Fatalf("expected the resource env label, got %s", data.Labels)
+	}
+
+	// team matches its default and is not drift.
+	if _, ok := labels["team"]; ok {
+		t.Fatalf("expected the default team label to be left out, got %s", data.Labels)
+	}
+
+	// owner was changed outside of Terraform and is reported.
+	if labels["owner"].String() != `"ops"` {
+		t.Fatalf("expected the changed owner label to be reported, got %s", data.Labels)
+	}
+}
+
+func TestRuntimeGroupReadManagedByLabel(t *testing.T) {
+	body := `{"id":"rg-1","name":"test","labels":{"managed-by":"terraform","env":"dev"}}`
+	marker := client.WithDefaultLabels(map[string]string{managedByLabelKey: managedByLabelValue})
+
+	data := readRuntimeGroup(t, map[string]string{"/runtime-groups/rg-1": body}, map[string]tftypes.Value{
+		"id":     tftypes.NewValue(tftypes.String, "rg-1"),
+		"name":   tftypes.NewValue(tftypes.String, "test"),
+		"labels": stringMap(map[string]string{"env": "dev"}),
+	}, marker)
+
+	if want := map[string]attr.Value{"env": types.StringValue("dev")}; !reflect.DeepEqual(data.Labels.Elements(), want) {
+		t.Fatalf("expected labels %v without the marker, got %s", want, data.Labels)
+	}
+}
+
+func TestRuntimeGroupReadDefaultLabelTemplates(t *testing.T) {
+	body := `{"id":"rg-1","name":"edge","labels":{"env":"dev","owner":"edge-team","kind":"CLUSTER_TYPE_HYBRID"}}`
+	defaults := client.WithDefaultLabels(map[string]string{"owner": "$name-team", "kind": "$cluster_type"})
+
+	data := readRuntimeGroup(t, map[string]string{"/runtime-groups/rg-1": body}, map[string]tftypes.Value{
+		"id":           tftypes.NewValue(tftypes.String, "rg-1"),
+		"name":         tftypes.NewValue(tftypes.String, "edge"),
+		"cluster_type": tftypes.NewValue(tftypes.String, "CLUSTER_TYPE_HYBRID"),
+		"labels":       stringMap(map[string]string{"env": "dev"}),
+	}, defaults)
+
+	// The expanded default labels are not drift.
+	if want := map[string]attr.Value{"env": types.StringValue("dev")}; !reflect.DeepEqual(data.Labels.Elements(), want) {
+		t.Fatalf("expected labels %v without the default labels, got %s", want, data.Labels)
+	}
+}
+
+func TestRuntimeGroupReadDefaultLabelTemplatesClusterType(t *testing.T) {
+	tests := map[string]struct {
+		body string
+	}{
+		"returned by the API": {
+			body: `{"id":"rg-1","name":"edge","labels":{"kind":"CLUSTER_TYPE_K8S_INGRESS_CONTROLLER"},` +
+				`"config":{"cluster_type":"CLUSTER_TYPE_K8S_INGRESS_CONTROLLER"}}`,
+		},
+		"provider default": {
+			body: `{"id":"rg-1","name":"edge","labels":{"kind":"CLUSTER_TYPE_K8S_INGRESS_CONTROLLER"}}`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			data := readRuntimeGroup(t, map[string]string{"/runtime-groups/rg-1": tt.body}, map[string]tftypes.Value{
+				"id":   tftypes.NewValue(tftypes.String, "rg-1"),
+				"name": tftypes.NewValue(tftypes.String, "edge"),
+			},
+				client.WithDefaultLabels(map[string]string{"kind": "$cluster_type"}),
+				client.WithDefaultClusterType(client.ClusterTypeK8sIngressController),
+			)
+
+			// The default label expanded with the cluster type sent on create is not drift.
+			if !data.Labels.IsNull() {
+				t.Fatalf("expected no labels, got %s", data.Labels)
+			}
+		})
+	}
+}
+
+func TestRuntimeGroupReadStatus(t *testing.T) {
+	state := map[string]tftypes.Value{
+		"id":   tftypes.NewValue(tftypes.String, "rg-1"),
+		"name": tftypes.NewValue(tftypes.String, "test"),
+	}
+
+	data := readRuntimeGroup(t, map[string]string{
+		"/runtime-groups/rg-1":        `{"id":"rg-1","name":"test"}`,
+		"/runtime-groups/rg-1/status": `{"state":"connected","updated_at":"2022-11-04T20:10:06.927Z"}`,
+	}, state)
+
+	if data.Status.ValueString() != "connected" {
+		t.Fatalf("expected status connected, got %s", data.Status)
+	}
+
+	// A missing status endpoint leaves the status null.
+	data = readRuntimeGroup(t, map[string]string{
+		"/runtime-groups/rg-1": `{"id":"rg-1","name":"test"}`,
+	}, state)
+
+	if !data.Status.IsNull() {
+		t.Fatalf("expected a null status, got %s", data.Status)
+	}
+
+	// A status included in the runtime group is not fetched separately.
+	data = readRuntimeGroup(t, map[string]string{
+		"/runtime-groups/rg-1": `{"id":"rg-1","name":"test","status":{"state":"ready"}}`,
+	}, state)
+
+	if data.Status.ValueString() != "ready" {
+		t.Fatalf("expected status ready, got %s", data.Status)
+	}
+}
+
+func TestRuntimeGroupReadRawResponse(t *testing.T) {
+	body := `{"id":"rg-1","name":"test","entity_version":3}`
+
+	data := readRuntimeGroup(t, map[string]string{"/runtime-groups/rg-1": body}, map[string]tftypes.Value{
+		"id":   tftypes.NewValue(tftypes.String, "rg-1"),
+		"name": tftypes.NewValue(tftypes.String, "test"),
+	})
+
+	var raw map[string]any
+	if err := json.Unmarshal([]byte(data.RawResponse.ValueString()), &raw); err != nil {
+		t.Fatalf("expected valid JSON, got %s: %s", data.RawResponse, err)
+	}
+
+	// Fields the provider does not model are kept.
+	if raw["entity_version"] != float64(3) {
+		t.Fatalf("expected entity_version 3, got %v", raw["entity_version"])
+	}
+}
+
+// importRuntimeGroup runs the resource ImportState for the given import id
+// against a stub server answering the given routes.
+func importRuntimeGroup(t *testing.T, routes map[string]string, id string) resource.ImportStateResponse {
+	t.Helper()
+
+	server := testServer(t, routes)
+
+	ctx := context.Background()
+	r := &RuntimeGroup{clients: newClientRef(testClient(t, server.URL))}
+	schemaResp := runtimeGroupSchema(t)
+
+	resp := resource.ImportStateResponse{
+		State: tfsdk.State{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+		},
+	}
+
+	r.ImportState(ctx, resource.ImportStateRequest{ID: id}, &resp)
+
+	return resp
+}
+
+func TestRuntimeGroupImportStateInvalidID(t *testing.T) {
+	for _, id := range []string{"", "CLUSTER_TYPE_UNKNOWN/test", "CLUSTER_TYPE_HYBRID/"} {
+		t.Run(id, func(t *testing.T) {
+			resp := importRuntimeGroup(t, nil, id)
+
+			if !resp.Diagnostics.HasError() {
+				t.Fatal("expected an error diagnostic")
+			}
+
+			detail := resp.Diagnostics.Errors()[0].Detail()
+			for _, format := range []string{"`<id>`", "`<name>`", "`<cluster_type>/<name>`"} {
+				if !strings.Contains(detail, format) {
+					t.Errorf("expected the diagnostic to list %s, got: %s", format, detail)
+				}
+			}
+		})
+	}
+}
+
+func TestRuntimeGroupImportState(t *testing.T) {
+	const id = "7f9fd312-a987-4628-b4c5-bb4f4fddd5f7"
+
+	routes := map[string]string{
+		"/runtime-groups":       `{"meta":{"page":{"number":1,"size":10,"total":2}},"data":[{"id":"` + id + `","name":"test"},{"id":"other","name":"other"}]}`,
+		"/runtime-groups/" + id: `{"id":"` + id + `","name":"test"}`,
+	}
+
+	tests := map[string]struct {
+		importID        string
+		wantClusterType string
+	}{
+		"id":                {importID: id},
+		"name":              {importID: "test"},
+		"cluster type name": {importID: "CLUSTER_TYPE_HYBRID/test", wantClusterType: "CLUSTER_TYPE_HYBRID"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := importRuntimeGroup(t, routes, tt.importID)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var data RuntimeGroupModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			if data.Id.ValueString() != id {
+				t.Fatalf("expected id %s, got %s", id, data.Id)
+			}
+
+			if data.ClusterType.ValueString() != tt.wantClusterType {
+				t.Fatalf("expected cluster type %q, got %s", tt.wantClusterType, data.ClusterType)
+			}
+		})
+	}
+}
+
+func TestRuntimeGroupImportStateRead(t *testing.T) {
+	const id = "7f9fd312-a987-4628-b4c5-bb4f4fddd5f7"
+
+	routes := map[string]string{
+		"/runtime-groups/" + id: `{"id":"` + id + `","name":"test","description":"imported","labels":{"env":"test"},` +
+			`"config":{"control_plane_endpoint":"https://cp.example.com","telemetry_endpoint":"https://tp.example.com","cluster_type":"CLUSTER_TYPE_HYBRID"}}`,
+		"/runtime-groups/" + id + "/status": `{"state":"ready"}`,
+	}
+
+	importResp := importRuntimeGroup(t, routes, id)
+	if importResp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", importResp.Diagnostics)
+	}
+
+	// The framework reads the imported resource right after ImportState.
+	ctx := context.Background()
+	r := &RuntimeGroup{clients: newClientRef(testClient(t, testServer(t, routes).URL))}
+	resp := resource.ReadResponse{State: importResp.State}
+
+	r.Read(ctx, resource.ReadRequest{State: importResp.State}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data RuntimeGroupModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	labels := make(map[string]string)
+	resp.Diagnostics.Append(data.Labels.ElementsAs(ctx, &labels, false)...)
+
+	if data.Id.ValueString() != id || data.Name.ValueString() != "test" || data.Description.ValueString() != "imported" ||
+		labels["env"] != "test" || data.ControlPlaneEndpoint.ValueString() != "https://cp.example.com" ||
+		data.TelemetryEndpoint.ValueString() != "https://tp.example.com" || data.Status.ValueString() != "ready" ||
+		data.ClusterType.ValueString() != "CLUSTER_TYPE_HYBRID" || data.DeletionProtection.IsNull() || data.RawResponse.IsNull() {
+		t.Fatalf("expected a fully populated state, got %+v", data)
+	}
+}
+
+func TestRuntimeGroupImportStateNameNotFound(t *testing.T) {
+	resp := importRuntimeGroup(t, map[string]string{
+		"/runtime-groups": `{"meta":{"page":{"number":1,"size":10,"total":0}},"data":[]}`,
+	}, "missing")
+
+	if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != "Runtime Group Not Found" {
+		t.Fatalf("expected a not found diagnostic, got %v", resp.Diagnostics)
+	}
+}
+
+func TestRuntimeGroupImportStateNameFilter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("filter[name][eq]"); got != "test" {
+			t.Errorf("expected the runtime groups filtered by name, got %q", r.URL.RawQuery)
+		}
+
+		fmt.Fprint(w, `{"meta":{"page":{"number":1,"size":10,"total":1}},"data":[{"id":"rg-1","name":"test"}]}`)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	r := &RuntimeGroup{clients: newClientRef(testClient(t, server.URL))}
+	schemaResp := runtimeGroupSchema(t)
+
+	resp := resource.ImportStateResponse{
+		State: tfsdk.State{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+		},
+	}
+
+	r.ImportState(ctx, resource.ImportStateRequest{ID: "test"}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var id types.String
+	resp.Diagnostics.Append(resp.State.GetAttribute(ctx, path.Root("id"), &id)...)
+	if id.ValueString() != "rg-1" {
+		t.Fatalf("expected id rg-1, got %s", id)
+	}
+}
+
+func TestRuntimeGroupImportStateIDNotFound(t *testing.T) {
+	const id = "00000000-0000-0000-0000-000000000000"
+
+	resp := importRuntimeGroup(t, nil, id)
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error diagnostic")
+	}
+
+	diagnostic := resp.Diagnostics.Errors()[0]
+	if diagnostic.Summary() != "Runtime Group Not Found" || !strings.Contains(diagnostic.Detail(), "No runtime group with id \""+id+"\"") {
+		t.Fatalf("expected a not found diagnostic naming the id, got %v", resp.Diagnostics)
+	}
+
+	if !resp.State.Raw.IsNull() {
+		t.Fatalf("expected nothing to be imported, got %s", resp.State.Raw)
+	}
+}
+
+func TestRuntimeGroupCreateNamePrefix(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		var body client.CreateRuntimeGroupRequest
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("decoding request body: %s", err)
+		}
+
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"id":"rg-1","name":%q}`, body.Name)
+	}))
+	defer server.Close()
+
+	var names []string
+	for i := 0; i < 2; i++ {
+		resp := createRuntimeGroup(t, testClient(t, server.URL), map[string]tftypes.Value{
+			"name":        tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			"name_prefix": tftypes.NewValue(tftypes.String, "ephemeral-"),
+		})
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+		}
+
+		var data RuntimeGroupModel
+		resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+		if resp.Diagnostics.HasError() {
+			t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+		}
+
+		name := data.Name.ValueString()
+		if !regexp.MustCompile(`^ephemeral-\d{14}[0-9a-f]{8}$`).MatchString(name) {
+			t.Fatalf("expected a name generated from the prefix, got %q", name)
+		}
+
+		names = append(names, name)
+	}
+
+	if names[0] == names[1] {
+		t.Fatalf("expected unique names, got %q twice", names[0])
+	}
+}
+
+func TestRuntimeGroupCreateDefaultClusterType(t *testing.T) {
+	tests := map[string]struct {
+		defaultClusterType client.ClusterType
+		clusterType        tftypes.Value
+		want               client.ClusterType
+	}{
+		"no default": {
+			clusterType: tftypes.NewValue(tftypes.String, nil),
+		},
+		"inherited": {
+			defaultClusterType: client.ClusterTypeK8sIngressController,
+			clusterType:        tftypes.NewValue(tftypes.String, nil),
+			want:               client.ClusterTypeK8sIngressController,
+		},
+		"overridden": {
+			defaultClusterType: client.ClusterTypeK8sIngressController,
+			clusterType:        tftypes.NewValue(tftypes.String, string(client.ClusterTypeHybrid)),
+			want:               client.ClusterTypeHybrid,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got client.ClusterType
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				var body client.CreateRuntimeGroupRequest
+				if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+					t.Errorf("decoding request body: %s", err)
+				}
+				got = body.ClusterType
+
+				w.WriteHeader(http.StatusCreated)
+				fmt.Fprint(w, `{"id":"rg-1","name":"test"}`)
+			}))
+			defer server.Close()
+
+			resp := createRuntimeGroup(t, testClient(t, server.URL, client.WithDefaultClusterType(tt.defaultClusterType)), map[string]tftypes.Value{
+				"name":         tftypes.NewValue(tftypes.String, "test"),
+				"cluster_type": tt.clusterType,
+			})
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			if got != tt.want {
+				t.Fatalf("expected cluster type %q, got %q", tt.want, got)
+			}
+
+			// The state holds the cluster type sent, null when the API picked it.
+			var data RuntimeGroupModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+			if data.ClusterType.ValueString() != string(tt.want) || data.ClusterType.IsNull() != (tt.want == "") {
+				t.Fatalf("expected cluster_type %q in state, got %s", tt.want, data.ClusterType)
+			}
+		})
+	}
+}
+
+func TestRuntimeGroupValidateConfigName(t *testing.T) {
+	tests := map[string]struct {
+		config  map[string]tftypes.Value
+		wantErr bool
+	}{
+		"name": {
+			config: map[string]tftypes.Value{"name": tftypes.NewValue(tftypes.String, "test")},
+		},
+		"name_prefix": {
+			config: map[string]tftypes.Value{"name_prefix": tftypes.NewValue(tftypes.String, "test-")},
+		},
+		"both": {
+			config: map[string]tftypes.Value{
+				"name":        tftypes.NewValue(tftypes.String, "test"),
+				"name_prefix": tftypes.NewValue(tftypes.String, "test-"),
+			},
+			wantErr: true,
+		},
+		"neither": {
+			config:  map[string]tftypes.Value{},
+			wantErr: true,
+		}, "transfer and archive on destroy": {
+			config: map[string]tftypes.Value{
+				"name":                tftypes.NewValue(tftypes.String, "test"),
+				"on_destroy":          tftypes.NewValue(tftypes.String, "archive"),
+				"transfer_on_destroy": tftypes.NewValue(tftypes.String, "org-2"),
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			schemaResp := runtimeGroupSchema(t)
+
+			req := resource.ValidateConfigRequest{
+				Config: tfsdk.Config{
+					Schema: schemaResp.Schema,
+					Raw:    testObject(t, schemaResp.Schema.Type(), tt.config),
+				},
+			}
+
+			var resp resource.ValidateConfigResponse
+			(&RuntimeGroup{}).ValidateConfig(ctx, req, &resp)
+
+			if resp.Diagnostics.HasError() != tt.wantErr {
+				t.Fatalf("expected error %t, got %v", tt.wantErr, resp.Diagnostics)
+			}
+		})
+	}
+}
+
+func TestRuntimeGroupReadMalformedEndpoint(t *testing.T) {
+	server := testServer(t, map[string]string{
+		"/runtime-groups/rg-1": `{"id":"rg-1","name":"test","config":{"control_plane_endpoint":"cp.example.com:443","telemetry_endpoint":"https://tp.example.com"}}`,
+	})
+
+	ctx := context.Background()
+	r := &RuntimeGroup{clients: newClientRef(testClient(t, server.URL))}
+	schemaResp := runtimeGroupSchema(t)
+
+	priorState := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw: testObject(t, schemaResp.Schema.Type(), map[string]tftypes.Value{
+			"id":   tftypes.NewValue(tftypes.String, "rg-1"),
+			"name": tftypes.NewValue(tftypes.String, "test"),
+		}),
+	}
+	resp := resource.ReadResponse{State: priorState}
+
+	r.Read(ctx, resource.ReadRequest{State: priorState}, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	warnings := resp.Diagnostics.Warnings()
+	if len(warnings) != 1 || warnings[0].Summary() != "Malformed Endpoint" || !strings.Contains(warnings[0].Detail(), "control_plane_endpoint") {
+		t.Fatalf("expected a single malformed control_plane_endpoint warning, got %v", warnings)
+	}
+
+	// The raw value is still stored.
+	var data RuntimeGroupModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &data)...)
+	if data.ControlPlaneEndpoint.ValueString() != "cp.example.com:443" {
+		t.Fatalf("expected the raw endpoint to be stored, got %s", data.ControlPlaneEndpoint)
+	}
+}
+
+func TestRuntimeGroupCreateMalformedEndpoint(t *testing.T) {
+	server := testServer(t, map[string]string{
+		"/runtime-groups":      `{"id":"rg-1","name":"test","config":{"control_plane_endpoint":"https://cp.example.com","telemetry_endpoint":"tp.example.com"}}`,
+		"/runtime-groups/rg-1": `{"id":"rg-1","name":"test"}`,
+	})
+
+	resp := createRuntimeGroup(t, testClient(t, server.URL), map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "test"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	warnings := resp.Diagnostics.Warnings()
+	if len(warnings) != 1 || warnings[0].Summary() != "Malformed Endpoint" || !strings.Contains(warnings[0].Detail(), "telemetry_endpoint") {
+		t.Fatalf("expected a single malformed telemetry_endpoint warning, got %v", warnings)
+	}
+
+	// The created runtime group is still saved with its id.
+	var data RuntimeGroupModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+	if data.Id.ValueString() != "rg-1" || data.TelemetryEndpoint.ValueString() != "tp.example.com" {
+		t.Fatalf("expected the created runtime group in state, got id %s and telemetry_endpoint %s", data.Id, data.TelemetryEndpoint)
+	}
+}
+
+func TestRuntimeGroupCreateAccepted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost:
+			w.Header().Set("Location", "/jobs/job-1")
+			w.WriteHeader(http.StatusAccepted)
+		case r.URL.Path == "/jobs/job-1":
+			fmt.Fprint(w, `{"status":"succeeded","runtime_group_id":"rg-1"}`)
+		case r.URL.Path == "/runtime-groups/rg-1":
+			fmt.Fprint(w, `{"id":"rg-1","name":"test","config":{"control_plane_endpoint":"https://cp.example.com","telemetry_endpoint":"https://tp.example.com"}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	resp := createRuntimeGroup(t, testClient(t, server.URL), map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "test"),
+	})
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data RuntimeGroupModel
+	resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+	if data.Id.ValueString() != "rg-1" || data.ControlPlaneEndpoint.ValueString() != "https://cp.example.com" {
+		t.Fatalf("expected the runtime group created by the job in state, got id %s and control_plane_endpoint %s", data.Id, data.ControlPlaneEndpoint)
+	}
+}
+
+func TestRuntimeGroupDuplicatedEndpoints(t *testing.T) {
+	group := `{"id":"rg-1","name":"test","config":{"control_plane_endpoint":"https://cp.example.com","telemetry_endpoint":"https://cp.example.com"}}`
+	server := testServer(t, map[string]string{
+		"/runtime-groups":      group,
+		"/runtime-groups/rg-1": group,
+	})
+
+	created := createRuntimeGroup(t, testClient(t, server.URL), map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "test"),
+	})
+
+	ctx := context.Background()
+	r := &RuntimeGroup{clients: newClientRef(testClient(t, server.URL))}
+	schemaResp := runtimeGroupSchema(t)
+
+	priorState := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw: testObject(t, schemaResp.Schema.Type(), map[string]tftypes.Value{
+			"id":   tftypes.NewValue(tftypes.String, "rg-1"),
+			"name": tftypes.NewValue(tftypes.String, "test"),
+		}),
+	}
+	read := resource.ReadResponse{State: priorState}
+	r.Read(ctx, resource.ReadRequest{State: priorState}, &read)
+
+	for name, diags := range map[string]diag.Diagnostics{"create": created.Diagnostics, "read": read.Diagnostics} {
+		if diags.HasError() {
+			t.Fatalf("%s: unexpected diagnostics: %v", name, diags)
+		}
+
+		warnings := diags.Warnings()
+		if len(warnings) != 1 || warnings[0].Summary() != "Duplicated Endpoint" {
+			t.Fatalf("%s: expected a single duplicated endpoint warning, got %v", name, warnings)
+		}
+	}
+}
+
+// deleteRuntimeGroup runs the resource Delete for the given prior state.
+func deleteRuntimeGroup(t *testing.T, c *client.Client, state map[string]tftypes.Value) resource.DeleteResponse {
+	t.Helper()
+
+	ctx := context.Background()
+	r := &RuntimeGroup{clients: newClientRef(c)}
+	schemaResp := runtimeGroupSchema(t)
+
+	priorState := tfsdk.State{
+		Schema: schemaResp.Schema,
+		Raw:    testObject(t, schemaResp.Schema.Type(), state),
+	}
+	resp := resource.DeleteResponse{State: priorState}
+
+	r.Delete(ctx, resource.DeleteRequest{State: priorState}, &resp)
+
+	return resp
+}
+
+func TestRuntimeGroupDeleteProtection(t *testing.T) {
+	tests := map[string]struct {
+		protected    bool
+		wantRequests int
+	}{
+		"protected":   {protected: true, wantRequests: 0},
+		"unprotected": {protected: false, wantRequests: 1},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var requests int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+
+				if r.Method != http.MethodDelete || r.URL.Path != "/runtime-groups/rg-1" {
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+				}
+
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			resp := deleteRuntimeGroup(t, testClient(t, server.URL), map[string]tftypes.Value{
+				"id":                  tftypes.NewValue(tftypes.String, "rg-1"),
+				"name":                tftypes.NewValue(tftypes.String, "production"),
+				"deletion_protection": tftypes.NewValue(tftypes.Bool, tt.protected),
+			})
+
+			if resp.Diagnostics.HasError() != tt.protected {
+				t.Fatalf("expected error %t, got %v", tt.protected, resp.Diagnostics)
+			}
+
+			if tt.protected && resp.Diagnostics.Errors()[0].Summary() != "Runtime Group Deletion Protected" {
+				t.Fatalf("unexpected diagnostic: %v", resp.Diagnostics)
+			}
+
+			if requests != tt.wantRequests {
+				t.Fatalf("expected %d requests, got %d", tt.wantRequests, requests)
+			}
+		})
+	}
+}
+
+func TestRuntimeGroupDeleteOnDestroy(t *testing.T) {
+	tests := map[string]struct {
+		onDestroy  string
+		transfer   string
+		wantMethod string
+		wantPath   string
+	}{
+		"delete":   {wantMethod: http.MethodDelete, wantPath: "/runtime-groups/rg-1"},
+		"archive":  {onDestroy: "archive", wantMethod: http.MethodPost, wantPath: "/runtime-groups/rg-1/archive"},
+		"transfer": {transfer: "org-2", wantMethod: http.MethodPost, wantPath: "/runtime-groups/rg-1/transfer"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var requests []string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests = append(requests, r.Method+" "+r.URL.Path)
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			state := map[string]tftypes.Value{
+				"id":   tftypes.NewValue(tftypes.String, "rg-1"),
+				"name": tftypes.NewValue(tftypes.String, "test"),
+			}
+			if tt.onDestroy != "" {
+				state["on_destroy"] = tftypes.NewValue(tftypes.String, tt.onDestroy)
+			}
+			if tt.transfer != "" {
+				state["transfer_on_destroy"] = tftypes.NewValue(tftypes.String, tt.transfer)
+			}
+
+			resp := deleteRuntimeGroup(t, testClient(t, server.URL), state)
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			want := []string{tt.wantMethod + " " + tt.wantPath}
+			if !reflect.DeepEqual(requests, want) {
+				t.Fatalf("expected requests %q, got %q", want, requests)
+			}
+		})
+	}
+}
+
+func TestRotationTriggered(t *testing.T) {
+	tests := map[string]struct {
+		prior, planned types.String
+		want           bool
+	}{
+		"unchanged": {prior: types.StringValue("1"), planned: types.StringValue("1")},
+		"changed":   {prior: types.StringValue("1"), planned: types.StringValue("2"), want: true},
+		"added":     {prior: types.StringNull(), planned: types.StringValue("1"), want: true},
+		"unknown":   {prior: types.StringValue("1"), planned: types.StringUnknown(), want: true},
+		"removed":   {prior: types.StringValue("1"), planned: types.StringNull()},
+		"never set": {prior: types.StringNull(), planned: types.StringNull()},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := rotationTriggered(tt.prior, tt.planned); got != tt.want {
+				t.Fatalf("expected %t, got %t", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRuntimeGroupUpdateRotateOn(t *testing.T) {
+	tests := map[string]struct {
+		rotateOn     string
+		wantRotation bool
+	}{
+		"unchanged": {rotateOn: "2023-01-01"},
+		"changed":   {rotateOn: "2023-06-01", wantRotation: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var rotations int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.Method + " " + r.URL.Path {
+				case "PATCH /runtime-groups/rg-1":
+					fmt.Fprint(w, `{"id":"rg-1","name":"test","config":{"control_plane_endpoint":"https://cp1.example.com"}}`)
+				case "POST /runtime-groups/rg-1/rotate-credentials":
+					rotations++
+					fmt.Fprint(w, `{"id":"rg-1","name":"test","config":{"control_plane_endpoint":"https://cp2.example.com"}}`)
+				default:
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+				}
+			}))
+			defer server.Close()
+
+			resp := updateRuntimeGroup(t, testClient(t, server.URL), map[string]tftypes.Value{
+				"id":        tftypes.NewValue(tftypes.String, "rg-1"),
+				"name":      tftypes.NewValue(tftypes.String, "test"),
+				"rotate_on": tftypes.NewValue(tftypes.String, tt.rotateOn),
+			}, map[string]tftypes.Value{
+				"id":        tftypes.NewValue(tftypes.String, "rg-1"),
+				"name":      tftypes.NewValue(tftypes.String, "test"),
+				"rotate_on": tftypes.NewValue(tftypes.String, "2023-01-01"),
+			})
+			if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var data RuntimeGroupModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+
+			want := "https://cp1.example.com"
+			if tt.wantRotation {
+				want = "https://cp2.example.com"
+			}
+
+			if data.ControlPlaneEndpoint.ValueString() != want {
+				t.Fatalf("expected control_plane_endpoint %s, got %s", want, data.ControlPlaneEndpoint)
+			}
+
+			if (rotations == 1) != tt.wantRotation || rotations > 1 {
+				t.Fatalf("expected rotation %t, got %d rotations", tt.wantRotation, rotations)
+			}
+		})
+	}
+}
+
+func TestRuntimeGroupModifyPlanRotateOn(t *testing.T) {
+	ctx := context.Background()
+	schemaResp := runtimeGroupSchema(t)
+
+	values := func(rotateOn string) map[string]tftypes.Value {
+		return map[string]tftypes.Value{
+			"id":                     tftypes.NewValue(tftypes.String, "rg-1"),
+			"name":                   tftypes.NewValue(tftypes.String, "test"),
+			"control_plane_endpoint": tftypes.NewValue(tftypes.String, "https://cp1.example.com"),
+			"rotate_on":              tftypes.NewValue(tftypes.String, rotateOn),
+		}
+	}
+
+	plan := tfsdk.Plan{Schema: schemaResp.Schema, Raw: testObject(t, schemaResp.Schema.Type(), values("2"))}
+	req := resource.ModifyPlanRequest{
+		State: tfsdk.State{Schema: schemaResp.Schema, Raw: testObject(t, schemaResp.Schema.Type(), values("1"))},
+		Plan:  plan,
+	}
+	resp := resource.ModifyPlanResponse{Plan: plan}
+
+	(&RuntimeGroup{}).ModifyPlan(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var endpoint types.String
+	resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("control_plane_endpoint"), &endpoint)...)
+	if !endpoint.IsUnknown() {
+		t.Fatalf("expected an unknown control_plane_endpoint, got %s", endpoint)
+	}
+}
+
+func TestRuntimeGroupModelToCreateRequest(t *testing.T) {
+	ctx := context.Background()
+
+	tests := map[string]struct {
+		labels types.Map
+		want   map[string]string
+	}{
+		"labels": {
+			labels: types.MapValueMust(types.StringType, map[string]attr.Value{"env": types.StringValue("test")}),
+			want:   map[string]string{"env": "test"},
+		},
+		"null labels": {
+			labels: types.MapNull(types.StringType),
+			want:   map[string]string{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := RuntimeGroupModel{
+				Name:        types.StringValue("test"),
+				Description: types.StringValue("description"),
+				ClusterType: types.StringValue("CLUSTER_TYPE_HYBRID"),
+				Labels:      tt.labels,
+			}
+
+			got, diags := m.toCreateRequest(ctx)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", diags)
+			}
+
+			want := client.CreateRuntimeGroupRequest{
+				Name:        "test",
+				Description: "description",
+				ClusterType: client.ClusterTypeHybrid,
+				Labels:      tt.want,
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("expected %+v, got %+v", want, got)
+			}
+		})
+	}
+}
+
+func TestRuntimeGroupCreateWaitForReady(t *testing.T) {
+	interval := readyPollInterval
+	readyPollInterval = time.Millisecond
+	t.Cleanup(func() { readyPollInterval = interval })
+
+	tests := map[string]struct {
+		states     []string
+		wantStatus string
+		wantError  string
+	}{
+		"ready after polling": {
+			states:     []string{"provisioning", "provisioning", "ready"},
+			wantStatus: "ready",
+		},
+		"never ready": {
+			states:    []string{"provisioning"},
+			wantError: "Operation Timed Out",
+		},
+	}
+
+	timeoutsType := runtimeGroupSchema(t).Schema.Type().TerraformType(context.Background()).(tftypes.Object).AttributeTypes["timeouts"].(tftypes.Object)
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var reads int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost {
+					w.WriteHeader(http.StatusCreated)
+					fmt.Fprint(w, `{"id":"rg-1","name":"test"}`)
+					return
+				}
+
+				state := tt.states[len(tt.states)-1]
+				if reads < len(tt.states) {
+					state = tt.states[reads]
+				}
+				reads++
+
+				fmt.Fprintf(w, `{"state":%q}`, state)
+			}))
+			defer server.Close()
+
+			resp := createRuntimeGroup(t, testClient(t, server.URL), map[string]tftypes.Value{
+				"name":           tftypes.NewValue(tftypes.String, "test"),
+				"wait_for_ready": tftypes.NewValue(tftypes.Bool, true),
+				"timeouts": tftypes.NewValue(timeoutsType, map[string]tftypes.Value{
+					"create": tftypes.NewValue(tftypes.String, "100ms"),
+					"read":   tftypes.NewValue(tftypes.String, nil),
+					"update": tftypes.NewValue(tftypes.String, nil),
+					"delete": tftypes.NewValue(tftypes.String, nil),
+				}),
+			})
+
+			if tt.wantError != "" {
+				if !resp.Diagnostics.HasError() || resp.Diagnostics.Errors()[0].Summary() != tt.wantError {
+					t.Fatalf("expected a %q diagnostic, got %v", tt.wantError, resp.Diagnostics)
+				}
+			} else if resp.Diagnostics.HasError() {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			var data RuntimeGroupModel
+			resp.Diagnostics.Append(resp.State.Get(context.Background(), &data)...)
+
+			// The runtime group is kept in the state either way.
+			if data.Id.ValueString() != "rg-1" {
+				t.Fatalf("expected id rg-1 in the state, got %s", data.Id)
+			}
+
+			if tt.wantStatus != "" && data.Status.ValueString() != tt.wantStatus {
+				t.Fatalf("expected status %s, got %s", tt.wantStatus, data.Status)
+			}
+
+			if reads < len(tt.states) {
+				t.Fatalf("expected at least %d status reads, got %d", len(tt.states), reads)
+			}
+		})
+	}
+}
+
+func TestRuntimeGroupReadEntityVersion(t *testing.T) {
+	tests := map[string]struct {
+		body string
+		want types.Int64
+	}{
+		"returned": {
+			body: `{"id":"rg-1","name":"test","entity_version":7}`,
+			want: types.Int64Value(7),
+		},
+		"not returned": {
+			body: `{"id":"rg-1","name":"test"}`,
+			want: types.Int64Null(),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			data := readRuntimeGroup(t, map[string]string{"/runtime-groups/rg-1": tt.body}, map[string]tftypes.Value{
+				"id": tftypes.NewValue(tftypes.String, "rg-1"),
+			})
+
+			if !data.EntityVersion.Equal(tt.want) {
+				t.Fatalf("expected entity_version %s, got %s", tt.want, data.EntityVersion)
+			}
+		})
+	}
+}
+
+func TestLabelCountValidator(t *testing.T) {
+	labels := func(n int) types.Map {
+		elements := make(map[string]attr.Value, n)
+		for i := 0; i < n; i++ {
+			elements[fmt.Sprintf("key-%d", i)] = types.StringValue("value")
+		}
+
+		return types.MapValueMust(types.StringType, elements)
+	}
+
+	tests := map[string]struct {
+		value   types.Map
+		wantErr bool
+	}{
+		"null":        {value: types.MapNull(types.StringType)},
+		"at max":      {value: labels(maxLabels)},
+		"above max":   {value: labels(maxLabels + 1), wantErr: true},
+		"empty":       {value: labels(0)},
+		"unknown map": {value: types.MapUnknown(types.StringType)},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := validator.MapRequest{
+				Path:        path.Root("labels"),
+				ConfigValue: tt.value,
+			}
+
+			var resp validator.MapResponse
+			labelCountValidator{}.ValidateMap(context.Background(), req, &resp)
+
+			if resp.Diagnostics.HasError() != tt.wantErr {
+				t.Fatalf("expected error %t, got %v", tt.wantErr, resp.Diagnostics)
+			}
+
+			if tt.wantErr {
+				want := fmt.Sprintf("at most %d labels per runtime group, got: %d", maxLabels, maxLabels+1)
+				if detail := resp.Diagnostics[0].Detail(); !strings.Contains(detail, want) {
+					t.Fatalf("expected detail to contain %q, got %q", want, detail)
+				}
+			}
+		})
+	}
+}
+
+func TestRuntimeGroupUpgradeStateV0(t *testing.T) {
+	ctx := context.Background()
+	server, err := providerserver.NewProtocol6WithError(New("test")())()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Terraform fetches the schemas first, registering the resource types.
+	if _, err := server.GetProviderSchema(ctx, &tfprotov6.GetProviderSchemaRequest{}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// The state written by a release before the label fix, lacking the newer
+	// attributes.
+	legacy := `{
+		"id": "rg-1",
+		"name": "test",
+		"cluster_type": "CLUSTER_TYPE_HYBRID",
+		"labels": {"env": "\"dev\"", "team": "\"a \\\"b\\\"\"", "plain": "prod"}
+	}`
+
+	resp, err := server.UpgradeResourceState(ctx, &tfprotov6.UpgradeResourceStateRequest{
+		TypeName: "scaffolding_runtime_group",
+		Version:  0,
+		RawState: &tfprotov6.RawState{JSON: []byte(legacy)},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov6.DiagnosticSeverityError {
+			t.Fatalf("unexpected diagnostic: %s: %s", d.Summary, d.Detail)
+		}
+	}
+
+	schemaResp := runtimeGroupSchema(t)
+	raw, err := resp.UpgradedState.Unmarshal(schemaResp.Schema.Type().TerraformType(ctx))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var got RuntimeGroupModel
+	state := tfsdk.State{Schema: schemaResp.Schema, Raw: raw}
+	if diags := state.Get(ctx, &got); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	labels := make(map[string]string)
+	got.Labels.ElementsAs(ctx, &labels, false)
+
+	want := map[string]string{"env": "dev", "team": `a "b"`, "plain": "prod"}
+	if !reflect.DeepEqual(labels, want) {
+		t.Fatalf("expected labels %v, got %v", want, labels)
+	}
+
+	if got.Id.ValueString() != "rg-1" || got.Name.ValueString() != "test" {
+		t.Fatalf("expected the other attributes to be kept, got %+v", got)
+	}
+
+	if !got.OnDestroy.IsNull() || !got.Timeouts.IsNull() || !got.Status.IsNull() {
+		t.Fatalf("expected the newer attributes to be null, got %+v", got)
+	}
+}
+
+func TestRuntimeGroupSchemaV0Frozen(t *testing.T) {
+	priorSchema := (&RuntimeGroup{}).UpgradeState(context.Background())[0].PriorSchema
+
+	// The attributes added since version 0 must not leak into its schema.
+	for _, name := range []string{"timeouts", "on_destroy", "deletion_protection", "status"} {
+		if _, ok := priorSchema.GetAttributes()[name]; ok {
+			t.Errorf("expected no %s attribute in the version 0 schema", name)
+		}
+		if _, ok := priorSchema.GetBlocks()[name]; ok {
+			t.Errorf("expected no %s block in the version 0 schema", name)
+		}
+	}
+
+	if priorSchema.GetVersion() != 0 {
+		t.Errorf("expected version 0, got %d", priorSchema.GetVersion())
+	}
+}
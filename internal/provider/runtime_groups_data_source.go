@@ -0,0 +1,201 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/client"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &RuntimeGroupsDataSource{}
+
+func NewRuntimeGroupsDataSource() datasource.DataSource {
+	return &RuntimeGroupsDataSource{}
+}
+
+// RuntimeGroupsDataSource defines the data source listing runtime groups.
+type RuntimeGroupsDataSource struct {
+	clients *clientRef
+}
+
+// RuntimeGroupsDataSourceModel describes the data source data model.
+type RuntimeGroupsDataSourceModel struct {
+	Limit         types.Int64              `tfsdk:"limit"`
+	RuntimeGroups []RuntimeGroupsItemModel `tfsdk:"runtime_groups"`
+	Total         types.Int64              `tfsdk:"total"`
+}
+
+// RuntimeGroupsItemModel describes a single runtime group of the list.
+type RuntimeGroupsItemModel struct {
+	Id                   types.String `tfsdk:"id"`
+	Name                 types.String `tfsdk:"name"`
+	Description          types.String `tfsdk:"description"`
+	Labels               types.Map    `tfsdk:"labels"`
+	ControlPlaneEndpoint types.String `tfsdk:"control_plane_endpoint"`
+	TelemetryEndpoint    types.String `tfsdk:"telemetry_endpoint"`
+	Status               types.String `tfsdk:"status"`
+}
+
+func (d *RuntimeGroupsDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_runtime_groups"
+}
+
+func (d *RuntimeGroupsDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Lists the runtime groups of the Konnect organization.",
+
+		Attributes: map[string]schema.Attribute{
+			"limit": schema.Int64Attribute{
+				MarkdownDescription: "The maximum number of runtime groups to return. Pagination stops once that many are gathered. `0` means unlimited.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"runtime_groups": schema.ListNestedAttribute{
+				MarkdownDescription: "The runtime groups of the organization, sorted by name.",
+				Computed:            true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							MarkdownDescription: "Service generated identifier for the Runtime Group.",
+							Computed:            true,
+						},
+						"name": schema.StringAttribute{
+							MarkdownDescription: "The name of the runtime group.",
+							Computed:            true,
+						},
+						"description": schema.StringAttribute{
+							MarkdownDescription: "The description of the runtime group in Konnect.",
+							Computed:            true,
+						},
+						"labels": schema.MapAttribute{
+							MarkdownDescription: "Labels of the runtime group.",
+							Computed:            true,
+							ElementType:         types.StringType,
+						},
+						"control_plane_endpoint": schema.StringAttribute{
+							MarkdownDescription: "Control Plane Endpoint.",
+							Computed:            true,
+						},
+						"telemetry_endpoint": schema.StringAttribute{
+							MarkdownDescription: "Telemetry Endpoint.",
+							Computed:            true,
+						},
+						"status": schema.StringAttribute{
+							MarkdownDescription: "The data plane connectivity status of the runtime group. Null when the API does not expose it. " +
+								"When the API does not include the statuses in the list, they are fetched with one request per runtime group, several at once.",
+							Computed: true,
+						},
+					},
+				},
+			},
+			"total": schema.Int64Attribute{
+				MarkdownDescription: "The total number of runtime groups reported by the API. Null when the API does not report it.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *RuntimeGroupsDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	clients, ok := req.ProviderData.(*clientRef)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *clientRef, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.clients = clients
+}
+
+func (d *RuntimeGroupsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data RuntimeGroupsDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	listResp, err := d.clients.Get().ListAllRuntimeGroups(ctx, client.ListOptions{
+		Limit:   int(data.Limit.ValueInt64()),
+		Include: []string{client.IncludeStatus},
+	})
+	if err != nil {
+		resp.Diagnostics.Append(diagFromAPIError("list runtime groups", err)...)
+		return
+	}
+
+	// The statuses the API did not include are fetched concurrently rather than
+	// one request after the other.
+	var missingStatus []string
+	for _, group := range listResp.Data {
+		if group.Status == nil {
+			missingStatus = append(missingStatus, group.ID)
+		}
+	}
+
+	statuses, err := d.clients.Get().GetRuntimeGroupStatuses(ctx, missingStatus)
+	if err != nil {
+		resp.Diagnostics.Append(diagFromAPIError("read runtime group status", err)...)
+		return
+	}
+
+	data.RuntimeGroups = make([]RuntimeGroupsItemModel, 0, len(listResp.Data))
+	for i := range listResp.Data {
+		group := &listResp.Data[i]
+		labels, diags := types.MapValueFrom(ctx, types.StringType, group.Labels)
+		resp.Diagnostics.Append(diags...)
+
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		status := types.StringNull()
+		if group.Status == nil {
+			group.Status = statuses[group.ID]
+		}
+		if group.Status != nil {
+			status = types.StringValue(group.Status.State)
+		}
+
+		data.RuntimeGroups = append(data.RuntimeGroups, RuntimeGroupsItemModel{
+			Id:                   types.StringValue(group.ID),
+			Name:                 types.StringValue(group.Name),
+			Description:          types.StringValue(group.Description),
+			Labels:               labels,
+			ControlPlaneEndpoint: types.StringValue(group.Config.ControlPlaneEndpoint),
+			TelemetryEndpoint:    types.StringValue(group.Config.TelemetryEndpoint),
+			Status:               status,
+		})
+	}
+
+	// Servers that don't report a total leave the attribute null.
+	data.Total = types.Int64Null()
+	if total := listResp.Meta.Page.Total; total != nil {
+		data.Total = types.Int64Value(int64(*total))
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,99 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+// readRuntimeGroupsDataSource runs the data source Read for the given config
+// against a stub server answering the given routes.
+func readRuntimeGroupsDataSource(t *testing.T, routes map[string]string, config map[string]tftypes.Value) RuntimeGroupsDataSourceModel {
+	t.Helper()
+
+	server := testServer(t, routes)
+
+	ctx := context.Background()
+	d := &RuntimeGroupsDataSource{clients: newClientRef(testClient(t, server.URL))}
+
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+
+	req := datasource.ReadRequest{
+		Config: tfsdk.Config{
+			Schema: schemaResp.Schema,
+			Raw:    testObject(t, schemaResp.Schema.Type(), config),
+		},
+	}
+	resp := datasource.ReadResponse{
+		State: tfsdk.State{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+		},
+	}
+
+	d.Read(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data RuntimeGroupsDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	return data
+}
+
+func TestRuntimeGroupsDataSourceTotal(t *testing.T) {
+	data := readRuntimeGroupsDataSource(t, map[string]string{"/runtime-groups": `{"meta":{"page":{"number":1,"size":10,"total":2}},"data":[{"id":"a","name":"one","labels":{"env":"test"}},{"id":"b","name":"two"}]}`}, nil)
+
+	if !data.Total.Equal(types.Int64Value(2)) {
+		t.Fatalf("expected total 2, got %s", data.Total)
+	}
+
+	if len(data.RuntimeGroups) != 2 || data.RuntimeGroups[0].Name.ValueString() != "one" {
+		t.Fatalf("unexpected runtime groups: %v", data.RuntimeGroups)
+	}
+}
+
+func TestRuntimeGroupsDataSourceWithoutTotal(t *testing.T) {
+	data := readRuntimeGroupsDataSource(t, map[string]string{"/runtime-groups": `{"meta":{"page":{"number":1,"size":10}},"data":[{"id":"a","name":"one"}]}`}, nil)
+
+	if !data.Total.IsNull() {
+		t.Fatalf("expected null total, got %s", data.Total)
+	}
+}
+
+func TestRuntimeGroupsDataSourceLimit(t *testing.T) {
+	data := readRuntimeGroupsDataSource(t, map[string]string{"/runtime-groups": `{"meta":{"page":{"number":1,"size":3,"total":3}},"data":[{"id":"a"},{"id":"b"},{"id":"c"}]}`}, map[string]tftypes.Value{
+		"limit": tftypes.NewValue(tftypes.Number, 2),
+	})
+
+	if len(data.RuntimeGroups) != 2 {
+		t.Fatalf("expected 2 runtime groups, got %d", len(data.RuntimeGroups))
+	}
+}
+
+func TestRuntimeGroupsDataSourceStatus(t *testing.T) {
+	data := readRuntimeGroupsDataSource(t, map[string]string{
+		"/runtime-groups":          `{"meta":{"page":{"number":1,"size":10,"total":2}},"data":[{"id":"a"},{"id":"b"}]}`,
+		"/runtime-groups/a/status": `{"state":"connected"}`,
+	}, nil)
+
+	if data.RuntimeGroups[0].Status.ValueString() != "connected" {
+		t.Fatalf("expected status connected, got %s", data.RuntimeGroups[0].Status)
+	}
+
+	if !data.RuntimeGroups[1].Status.IsNull() {
+		t.Fatalf("expected a null status, got %s", data.RuntimeGroups[1].Status)
+	}
+}
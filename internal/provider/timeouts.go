@@ -0,0 +1,50 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/client"
+)
+
+// defaultTimeout bounds the CRUD operations not configured in the timeouts block.
+const defaultTimeout = 20 * time.Minute
+
+// operationTimer measures a CRUD operation from its start, to report how long
+// it waited when it times out.
+type operationTimer struct {
+	operation string
+	timeout   time.Duration
+	start     time.Time
+}
+
+// startOperation starts timing operation and returns a context bounded by timeout.
+func startOperation(ctx context.Context, operation string, timeout time.Duration) (context.Context, context.CancelFunc, operationTimer) {
+	timer := operationTimer{operation: operation, timeout: timeout, start: time.Now()}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+
+	return ctx, cancel, timer
+}
+
+// addError adds a diagnostic for err, which happened during phase. Timeouts
+// report the elapsed time and the phase in progress so timeout values can be
+// tuned, other errors are reported by diagFromAPIError for op.
+func (t operationTimer) addError(diags *diag.Diagnostics, phase, op string, err error) {
+	if !errors.Is(err, context.DeadlineExceeded) && !client.IsTimeout(err) {
+		diags.Append(diagFromAPIError(op, err)...)
+		return
+	}
+
+	diags.AddError(
+		"Operation Timed Out",
+		fmt.Sprintf("The %s operation timed out after %s (timeout %s) while %s. "+
+			"Increase the %s value of the timeouts block if the API is expected to be that slow, got error: %s",
+			t.operation, time.Since(t.start).Round(time.Millisecond), t.timeout, phase, t.operation, err),
+	)
+}
@@ -0,0 +1,58 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestRuntimeGroupCreateTimeoutDiagnostic(t *testing.T) {
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Hang until the client gives up.
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer server.Close()
+	defer close(done)
+
+	timeoutsType := runtimeGroupSchema(t).Schema.Type().TerraformType(context.Background()).(tftypes.Object).AttributeTypes["timeouts"].(tftypes.Object)
+
+	resp := createRuntimeGroup(t, testClient(t, server.URL), map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "test"),
+		"timeouts": tftypes.NewValue(timeoutsType, map[string]tftypes.Value{
+			"create": tftypes.NewValue(tftypes.String, "50ms"),
+			"read":   tftypes.NewValue(tftypes.String, nil),
+			"update": tftypes.NewValue(tftypes.String, nil),
+			"delete": tftypes.NewValue(tftypes.String, nil),
+		}),
+	})
+
+	if !resp.Diagnostics.HasError() {
+		t.Fatal("expected an error diagnostic")
+	}
+
+	diags := resp.Diagnostics.Errors()
+	if diags[0].Summary() != "Operation Timed Out" {
+		t.Fatalf("unexpected diagnostic: %s: %s", diags[0].Summary(), diags[0].Detail())
+	}
+
+	match := regexp.MustCompile(`timed out after (\S+) \(timeout 50ms\) while creating the runtime group`).FindStringSubmatch(diags[0].Detail())
+	if match == nil {
+		t.Fatalf("expected the elapsed time and phase in the diagnostic, got: %s", diags[0].Detail())
+	}
+
+	if elapsed, err := time.ParseDuration(match[1]); err != nil || elapsed < 50*time.Millisecond {
+		t.Fatalf("expected an elapsed time of at least 50ms, got %s", match[1])
+	}
+}
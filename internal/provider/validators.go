@@ -0,0 +1,155 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/client"
+)
+
+// reservedLabelKey reports whether key starts with a prefix reserved by
+// Konnect, see client.ReservedLabelPrefixes.
+func reservedLabelKey(key string) bool {
+	_, ok := client.ReservedLabelPrefix(key)
+	return ok
+}
+
+// maxLabels is the maximum number of labels Konnect accepts on a runtime group.
+const maxLabels = 50
+
+var _ validator.String = labelKeyValidator{}
+
+// labelKeyValidator validates a label key is 1-63 characters long and does
+// not start with a reserved prefix.
+type labelKeyValidator struct{}
+
+func (v labelKeyValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("Keys must be of length 1-63 characters, and cannot start with %s.", quoteList(client.ReservedLabelPrefixes()))
+}
+
+func (v labelKeyValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v labelKeyValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	key := req.ConfigValue.ValueString()
+
+	if length := len(key); length < 1 || length > 63 {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Label Key",
+			fmt.Sprintf("Label key %q must be of length 1-63 characters, got: %d.", key, length),
+		)
+
+		return
+	}
+
+	if prefix, ok := client.ReservedLabelPrefix(key); ok {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Label Key",
+			fmt.Sprintf("Label key %q cannot start with the reserved prefix %q.", key, prefix),
+		)
+	}
+}
+
+var _ validator.Map = labelCountValidator{}
+
+// labelCountValidator validates a map holds at most maxLabels labels.
+type labelCountValidator struct{}
+
+func (v labelCountValidator) Description(ctx context.Context) string {
+	return fmt.Sprintf("At most %d labels are allowed.", maxLabels)
+}
+
+func (v labelCountValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v labelCountValidator) ValidateMap(ctx context.Context, req validator.MapRequest, resp *validator.MapResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if count := len(req.ConfigValue.Elements()); count > maxLabels {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Too Many Labels",
+			fmt.Sprintf("Konnect allows at most %d labels per runtime group, got: %d.", maxLabels, count),
+		)
+	}
+}
+
+var _ validator.String = endpointValidator{}
+
+// endpointValidator validates a string is an absolute http or https URL.
+type endpointValidator struct{}
+
+func (v endpointValidator) Description(ctx context.Context) string {
+	return "Value must be an absolute http or https URL."
+}
+
+func (v endpointValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v endpointValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if err := client.ValidateEndpoint(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Endpoint",
+			fmt.Sprintf("Endpoint must be an absolute http or https URL, got error: %s", err),
+		)
+	}
+}
+
+var _ validator.String = labelTemplateValidator{}
+
+// labelTemplateValidator validates a default label value only references the
+// runtime group as $name or $cluster_type.
+type labelTemplateValidator struct{}
+
+func (v labelTemplateValidator) Description(ctx context.Context) string {
+	return "Value may only reference the runtime group as $name or $cluster_type, $$ escapes $."
+}
+
+func (v labelTemplateValidator) MarkdownDescription(ctx context.Context) string {
+	return "Value may only reference the runtime group as `$name` or `$cluster_type`, `$$` escapes `$`."
+}
+
+func (v labelTemplateValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	if err := client.ValidateLabelTemplate(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(
+			req.Path,
+			"Invalid Label Template",
+			fmt.Sprintf("Default label values may only reference $name and $cluster_type, got error: %s", err),
+		)
+	}
+}
+
+// quoteList renders values as a comma separated list of single quoted strings.
+func quoteList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, value := range values {
+		quoted[i] = "'" + value + "'"
+	}
+
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,133 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure provider defined types fully satisfy framework interfaces.
+var _ datasource.DataSource = &WhoamiDataSource{}
+
+func NewWhoamiDataSource() datasource.DataSource {
+	return &WhoamiDataSource{}
+}
+
+// WhoamiDataSource defines the data source exposing the identity of the
+// configured token.
+type WhoamiDataSource struct {
+	clients *clientRef
+}
+
+// WhoamiDataSourceModel describes the data source data model.
+type WhoamiDataSourceModel struct {
+	Subject        types.String `tfsdk:"subject"`
+	OrganizationId types.String `tfsdk:"organization_id"`
+	Scopes         types.List   `tfsdk:"scopes"`
+	Endpoint       types.String `tfsdk:"endpoint"`
+}
+
+func (d *WhoamiDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_whoami"
+}
+
+func (d *WhoamiDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Exposes the identity the provider acts as, decoded from the claims of the configured token, and the endpoint it calls, without calling the API.",
+
+		Attributes: map[string]schema.Attribute{
+			"subject": schema.StringAttribute{
+				MarkdownDescription: "The user or system account of the token, from the `sub` claim. Null when the token lacks it.",
+				Computed:            true,
+			},
+			"organization_id": schema.StringAttribute{
+				MarkdownDescription: "The Konnect organization of the token, from the `org_id` claim. Null when the token lacks it.",
+				Computed:            true,
+			},
+			"scopes": schema.ListAttribute{
+				MarkdownDescription: "The scopes granted to the token, from the `scope` or `scp` claim. Null when the token lacks both.",
+				Computed:            true,
+				ElementType:         types.StringType,
+			},
+			"endpoint": schema.StringAttribute{
+				MarkdownDescription: "The URL of the runtime groups API the provider calls, joined from the `endpoint` of the provider, or the one its `region` and `environment` select, and the base path.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (d *WhoamiDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Prevent panic if the provider has not been configured.
+	if req.ProviderData == nil {
+		return
+	}
+
+	clients, ok := req.ProviderData.(*clientRef)
+
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *clientRef, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+
+		return
+	}
+
+	d.clients = clients
+}
+
+func (d *WhoamiDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data WhoamiDataSourceModel
+
+	// Read Terraform configuration data into the model
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	identity, err := d.clients.Get().Identity()
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to decode the token identity, got error: %s", err))
+		return
+	}
+
+	// Missing claims leave the attributes null.
+	data.Subject = types.StringNull()
+	if identity.Subject != "" {
+		data.Subject = types.StringValue(identity.Subject)
+	}
+
+	data.OrganizationId = types.StringNull()
+	if identity.OrganizationID != "" {
+		data.OrganizationId = types.StringValue(identity.OrganizationID)
+	}
+
+	data.Scopes = types.ListNull(types.StringType)
+	if identity.Scopes != nil {
+		scopes, diags := types.ListValueFrom(ctx, types.StringType, identity.Scopes)
+		resp.Diagnostics.Append(diags...)
+		data.Scopes = scopes
+	}
+
+	endpoint, err := d.clients.Get().ResolveURL("/runtime-groups")
+	if err != nil {
+		resp.Diagnostics.AddError("Client Error", fmt.Sprintf("Unable to resolve the API endpoint, got error: %s", err))
+		return
+	}
+	data.Endpoint = types.StringValue(endpoint)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Save data into Terraform state
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
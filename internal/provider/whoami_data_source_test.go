@@ -0,0 +1,101 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package provider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-provider-scaffolding-framework/internal/client"
+)
+
+// readWhoamiDataSource runs the data source Read with a client holding a token
+// carrying the given claims.
+func readWhoamiDataSource(t *testing.T, claims jwt.MapClaims) WhoamiDataSourceModel {
+	t.Helper()
+
+	claims["exp"] = time.Now().Add(time.Hour).Unix()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("signing test token: %s", err)
+	}
+
+	// No server: the data source must not call the API.
+	c, err := client.New("http://127.0.0.1:0", token)
+	if err != nil {
+		t.Fatalf("creating client: %s", err)
+	}
+
+	ctx := context.Background()
+	d := &WhoamiDataSource{clients: newClientRef(c)}
+
+	var schemaResp datasource.SchemaResponse
+	d.Schema(ctx, datasource.SchemaRequest{}, &schemaResp)
+
+	req := datasource.ReadRequest{
+		Config: tfsdk.Config{
+			Schema: schemaResp.Schema,
+			Raw:    testObject(t, schemaResp.Schema.Type(), nil),
+		},
+	}
+	resp := datasource.ReadResponse{
+		State: tfsdk.State{
+			Schema: schemaResp.Schema,
+			Raw:    tftypes.NewValue(schemaResp.Schema.Type().TerraformType(ctx), nil),
+		},
+	}
+
+	d.Read(ctx, req, &resp)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	var data WhoamiDataSourceModel
+	resp.Diagnostics.Append(resp.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+	}
+
+	return data
+}
+
+func TestWhoamiDataSource(t *testing.T) {
+	data := readWhoamiDataSource(t, jwt.MapClaims{"sub": "user-1", "org_id": "org-1", "scope": "read write"})
+
+	if !data.Subject.Equal(types.StringValue("user-1")) {
+		t.Fatalf("expected subject user-1, got %s", data.Subject)
+	}
+
+	if !data.OrganizationId.Equal(types.StringValue("org-1")) {
+		t.Fatalf("expected organization_id org-1, got %s", data.OrganizationId)
+	}
+
+	var scopes []string
+	data.Scopes.ElementsAs(context.Background(), &scopes, false)
+	if len(scopes) != 2 || scopes[0] != "read" || scopes[1] != "write" {
+		t.Fatalf("expected scopes [read write], got %v", scopes)
+	}
+
+	if !data.Endpoint.Equal(types.StringValue("http://127.0.0.1:0/runtime-groups")) {
+		t.Fatalf("expected the runtime groups endpoint, got %s", data.Endpoint)
+	}
+}
+
+func TestWhoamiDataSourceMissingClaims(t *testing.T) {
+	data := readWhoamiDataSource(t, jwt.MapClaims{"sub": "user-1"})
+
+	if !data.Subject.Equal(types.StringValue("user-1")) {
+		t.Fatalf("expected subject user-1, got %s", data.Subject)
+	}
+
+	if !data.OrganizationId.IsNull() || !data.Scopes.IsNull() {
+		t.Fatalf("expected null organization_id and scopes, got %s and %s", data.OrganizationId, data.Scopes)
+	}
+}